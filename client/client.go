@@ -7,6 +7,8 @@ import (
 	"net/http"
 	"sync/atomic"
 	"time"
+
+	"github.com/Moranilt/http-utils/logger"
 )
 
 type Method string
@@ -37,6 +39,22 @@ func Timeout() time.Duration {
 
 type client struct {
 	client *http.Client
+	signer RequestSigner
+}
+
+// RequestSigner adds signature to outgoing requests
+type RequestSigner interface {
+	Sign(req *http.Request, body []byte) error
+}
+
+// Option is a function type that can be used to configure a Client.
+type Option func(*client)
+
+// Sign every request with signer. Request is signed after all headers are set.
+func WithSigner(signer RequestSigner) Option {
+	return func(c *client) {
+		c.signer = signer
+	}
 }
 
 type Client interface {
@@ -50,10 +68,16 @@ type Client interface {
 }
 
 // Create new Client instance
-func New() Client {
-	return &client{
+func New(options ...Option) Client {
+	c := &client{
 		client: &http.Client{},
 	}
+
+	for _, opt := range options {
+		opt(c)
+	}
+
+	return c
 }
 
 // Send request with method POST
@@ -93,6 +117,18 @@ func (c *client) Do(ctx context.Context, method Method, url string, body []byte,
 		}
 	}
 
+	if request.Header.Get(logger.HeaderRequestId) == "" {
+		if requestId := logger.RequestId(ctx); requestId != "" {
+			request.Header.Set(logger.HeaderRequestId, requestId)
+		}
+	}
+
+	if c.signer != nil {
+		if err := c.signer.Sign(request, body); err != nil {
+			return nil, err
+		}
+	}
+
 	request, _ = c.setRequestTimeout(request)
 	res, err := c.client.Do(request)
 	if err != nil {
@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/Moranilt/http-utils/logger"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -186,3 +187,41 @@ func TestGetIP(t *testing.T) {
 		})
 	}
 }
+
+func TestRequestIdForwarding(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	c := New()
+
+	t.Run("request id from context", func(t *testing.T) {
+		ctx := logger.ContextWithRequestId(context.Background(), "request-id")
+		resp, err := c.Get(ctx, server.URL, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, "request-id", resp.Request.Header.Get(logger.HeaderRequestId))
+	})
+
+	t.Run("request id from headers", func(t *testing.T) {
+		ctx := logger.ContextWithRequestId(context.Background(), "request-id")
+		headers := NewHeaders(map[string]string{
+			logger.HeaderRequestId: "custom-id",
+		})
+		resp, err := c.Get(ctx, server.URL, headers)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, "custom-id", resp.Request.Header.Get(logger.HeaderRequestId))
+	})
+
+	t.Run("without request id", func(t *testing.T) {
+		resp, err := c.Get(context.Background(), server.URL, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Empty(t, resp.Request.Header.Get(logger.HeaderRequestId))
+	})
+}
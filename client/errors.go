@@ -0,0 +1,191 @@
+package client
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/Moranilt/http-utils/tiny_errors"
+	go_json "github.com/goccy/go-json"
+)
+
+const (
+	ERR_CODE_RemoteError = 981
+)
+
+const (
+	ErrRemoteError = "unexpected response of remote service"
+)
+
+const (
+	contentTypeProblemJSON = "application/problem+json"
+	maxErrorBodySize       = 1 << 20
+)
+
+func init() {
+	tiny_errors.MustRegister(tiny_errors.Definition{
+		Code:        ERR_CODE_RemoteError,
+		Name:        "RemoteError",
+		Message:     ErrRemoteError,
+		HTTPStatus:  http.StatusBadGateway,
+		Description: "Remote service responded with error in unknown format",
+	})
+}
+
+type decodeConfig struct {
+	codes     map[int]int
+	remapCode func(code int) (int, bool)
+}
+
+// DecodeOption configures DecodeError
+type DecodeOption func(*decodeConfig)
+
+// Replace remote error codes with local ones. Codes which are not in the map are kept.
+// Remote code is added to details as "remote_code".
+func RemapCodes(codes map[int]int) DecodeOption {
+	return func(c *decodeConfig) {
+		c.codes = codes
+	}
+}
+
+// Replace remote error code with local one returned by remap. Code is kept if remap returns false.
+// Remote code is added to details as "remote_code".
+func RemapCodeFunc(remap func(code int) (int, bool)) DecodeOption {
+	return func(c *decodeConfig) {
+		c.remapCode = remap
+	}
+}
+
+func (c *decodeConfig) localCode(code int) (int, bool) {
+	if local, ok := c.codes[code]; ok {
+		return local, true
+	}
+	if c.remapCode != nil {
+		return c.remapCode(code)
+	}
+	return code, false
+}
+
+// Body of response.DefaultResponse
+type defaultResponse struct {
+	Error *struct {
+		Code    *int           `json:"code"`
+		Message string         `json:"message"`
+		Details map[string]any `json:"details"`
+	} `json:"error"`
+}
+
+// Convert error response of remote service to tiny_errors.ErrorHandler. Returns nil if status of response is less than 400.
+//
+// Body in format of response.DefaultResponse or problem details (RFC 9457) keeps remote code, message, details
+// and HTTP status of response. Other bodies are converted to error with code ERR_CODE_RemoteError and
+// HTTP status of response. Body is read, but can be read again by caller.
+//
+// Example:
+//
+//	res, err := c.Get(ctx, "http://users/users/42", nil)
+//	if err != nil {
+//		return nil, tiny_errors.Translate(err)
+//	}
+//	defer res.Body.Close()
+//	if remoteErr := client.DecodeError(res, client.RemapCodes(map[int]int{2000: ERR_UserNotFound})); remoteErr != nil {
+//		return nil, remoteErr
+//	}
+func DecodeError(res *http.Response, options ...DecodeOption) tiny_errors.ErrorHandler {
+	if res == nil || res.StatusCode < http.StatusBadRequest {
+		return nil
+	}
+
+	cfg := &decodeConfig{}
+	for _, opt := range options {
+		opt(cfg)
+	}
+
+	var body []byte
+	if res.Body != nil {
+		body, _ = io.ReadAll(io.LimitReader(res.Body, maxErrorBodySize))
+		res.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), res.Body), res.Body}
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(res.Header.Get("Content-Type"))
+	if mediaType == contentTypeProblemJSON {
+		if err, ok := decodeProblem(body, res.StatusCode, cfg); ok {
+			return err
+		}
+	}
+
+	var envelope defaultResponse
+	if decodeJSON(body, &envelope) == nil && envelope.Error != nil && envelope.Error.Code != nil {
+		return newRemoteError(*envelope.Error.Code, envelope.Error.Message, envelope.Error.Details, res.StatusCode, cfg)
+	}
+
+	if err, ok := decodeProblem(body, res.StatusCode, cfg); ok {
+		return err
+	}
+
+	return tiny_errors.New(ERR_CODE_RemoteError, tiny_errors.HTTPStatus(res.StatusCode))
+}
+
+// Convert problem details to error. Code is taken from "code" member, other extension members become details
+func decodeProblem(body []byte, status int, cfg *decodeConfig) (tiny_errors.ErrorHandler, bool) {
+	var members map[string]any
+	if decodeJSON(body, &members) != nil {
+		return nil, false
+	}
+	if _, ok := members["title"]; !ok {
+		if _, ok := members["detail"]; !ok {
+			return nil, false
+		}
+	}
+
+	code := ERR_CODE_RemoteError
+	if number, ok := members["code"].(go_json.Number); ok {
+		if value, err := number.Int64(); err == nil {
+			code = int(value)
+		}
+	}
+
+	message, _ := members["detail"].(string)
+	if message == "" {
+		message, _ = members["title"].(string)
+	}
+
+	var details map[string]any
+	for name, value := range members {
+		switch name {
+		case "type", "title", "status", "detail", "instance", "code", "meta":
+			continue
+		}
+		if details == nil {
+			details = make(map[string]any)
+		}
+		details[name] = value
+	}
+	return newRemoteError(code, message, details, status, cfg), true
+}
+
+// Decode JSON keeping numbers as go_json.Number
+func decodeJSON(body []byte, value any) error {
+	decoder := go_json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	return decoder.Decode(value)
+}
+
+func newRemoteError(code int, message string, details map[string]any, status int, cfg *decodeConfig) tiny_errors.ErrorHandler {
+	local, remapped := cfg.localCode(code)
+	options := []tiny_errors.ErrorOption{tiny_errors.HTTPStatus(status)}
+	if message != "" {
+		options = append(options, tiny_errors.Message(message))
+	}
+	for name, value := range details {
+		options = append(options, tiny_errors.DetailValue(name, value))
+	}
+	if remapped {
+		options = append(options, tiny_errors.DetailValue("remote_code", code))
+	}
+	return tiny_errors.New(local, options...)
+}
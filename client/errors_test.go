@@ -0,0 +1,110 @@
+package client
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Moranilt/http-utils/response"
+	"github.com/Moranilt/http-utils/tiny_errors"
+	go_json "github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+)
+
+func remoteResponse(t *testing.T, write func(w http.ResponseWriter)) *http.Response {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	write(rec)
+	return rec.Result()
+}
+
+func TestDecodeErrorDefaultResponse(t *testing.T) {
+	remoteErr := tiny_errors.New(2000, tiny_errors.Message("user %s not found", "42"), tiny_errors.Detail("user_id", "42"))
+	res := remoteResponse(t, func(w http.ResponseWriter) {
+		response.ErrorResponse(w, remoteErr, http.StatusNotFound)
+	})
+
+	err := DecodeError(res)
+
+	assert.Equal(t, 2000, err.GetCode())
+	assert.Equal(t, "user 42 not found", err.GetMessage())
+	assert.Equal(t, map[string]any{"user_id": "42"}, err.GetDetails())
+	assert.Equal(t, http.StatusNotFound, err.GetHTTPStatus())
+
+	body, readErr := io.ReadAll(res.Body)
+	assert.NoError(t, readErr)
+	assert.Contains(t, string(body), `"code":2000`)
+}
+
+func TestDecodeErrorProblem(t *testing.T) {
+	remoteErr := tiny_errors.New(2000, tiny_errors.Message("user not found"), tiny_errors.Detail("user_id", "42"))
+	res := remoteResponse(t, func(w http.ResponseWriter) {
+		response.ProblemResponse(w, remoteErr, http.StatusNotFound)
+	})
+
+	err := DecodeError(res)
+
+	assert.Equal(t, 2000, err.GetCode())
+	assert.Equal(t, "user not found", err.GetMessage())
+	assert.Equal(t, map[string]any{"user_id": "42"}, err.GetDetails())
+	assert.Equal(t, http.StatusNotFound, err.GetHTTPStatus())
+}
+
+func TestDecodeErrorNumbers(t *testing.T) {
+	res := remoteResponse(t, func(w http.ResponseWriter) {
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(`{"error":{"code":2001,"message":"limit","details":{"limit":10}},"body":null}`))
+	})
+
+	err := DecodeError(res)
+
+	assert.Equal(t, 2001, err.GetCode())
+	assert.Equal(t, map[string]any{"limit": go_json.Number("10")}, err.GetDetails())
+}
+
+func TestDecodeErrorRemap(t *testing.T) {
+	res := remoteResponse(t, func(w http.ResponseWriter) {
+		response.ErrorResponse(w, tiny_errors.New(2000, tiny_errors.Message("user not found")), http.StatusNotFound)
+	})
+
+	err := DecodeError(res, RemapCodes(map[int]int{2000: 5000}))
+
+	assert.Equal(t, 5000, err.GetCode())
+	assert.Equal(t, "user not found", err.GetMessage())
+	assert.Equal(t, map[string]any{"remote_code": 2000}, err.GetDetails())
+
+	res = remoteResponse(t, func(w http.ResponseWriter) {
+		response.ErrorResponse(w, tiny_errors.New(2001, tiny_errors.Message("forbidden")), http.StatusForbidden)
+	})
+	err = DecodeError(res, RemapCodeFunc(func(code int) (int, bool) {
+		return code + 3000, code >= 2000
+	}))
+	assert.Equal(t, 5001, err.GetCode())
+	assert.Equal(t, http.StatusForbidden, err.GetHTTPStatus())
+}
+
+func TestDecodeErrorUnknownBody(t *testing.T) {
+	res := remoteResponse(t, func(w http.ResponseWriter) {
+		http.Error(w, "bad gateway", http.StatusServiceUnavailable)
+	})
+
+	err := DecodeError(res)
+
+	assert.Equal(t, ERR_CODE_RemoteError, err.GetCode())
+	assert.Equal(t, ErrRemoteError, err.GetMessage())
+	assert.Equal(t, http.StatusServiceUnavailable, err.GetHTTPStatus())
+
+	body, _ := io.ReadAll(res.Body)
+	assert.Equal(t, "bad gateway", strings.TrimSpace(string(body)))
+}
+
+func TestDecodeErrorSuccess(t *testing.T) {
+	res := remoteResponse(t, func(w http.ResponseWriter) {
+		response.SuccessResponse(w, "ok", http.StatusOK)
+	})
+
+	assert.Nil(t, DecodeError(res))
+	assert.Nil(t, DecodeError(nil))
+}
@@ -0,0 +1,55 @@
+package database
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Moranilt/http-utils/tiny_errors"
+	"github.com/lib/pq"
+)
+
+func init() {
+	tiny_errors.RegisterTranslator(TranslatePQ)
+}
+
+// Translate *pq.Error by SQLSTATE code:
+//
+//   - unique_violation, serialization_failure, deadlock_detected - ERR_CODE_Conflict (409)
+//   - foreign_key_violation, not_null_violation, check_violation, invalid input - ERR_CODE_Unprocessable (422)
+//   - query_canceled - ERR_CODE_Timeout (504)
+//   - connection exceptions, too_many_connections, admin_shutdown - ERR_CODE_Unavailable (503)
+//
+// Name of violated constraint is added to details as "constraint".
+// Registered in tiny_errors by importing the package.
+func TranslatePQ(err error) tiny_errors.ErrorHandler {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return nil
+	}
+
+	options := []tiny_errors.ErrorOption{}
+	if pqErr.Constraint != "" {
+		options = append(options, tiny_errors.Detail("constraint", pqErr.Constraint))
+	}
+
+	switch pqErr.Code.Name() {
+	case "unique_violation", "serialization_failure", "deadlock_detected":
+		return wrap(err, tiny_errors.ERR_CODE_Conflict, http.StatusConflict, tiny_errors.ErrConflict, options)
+	case "foreign_key_violation", "not_null_violation", "check_violation", "exclusion_violation",
+		"invalid_text_representation", "string_data_right_truncation", "numeric_value_out_of_range":
+		return wrap(err, tiny_errors.ERR_CODE_Unprocessable, http.StatusUnprocessableEntity, tiny_errors.ErrUnprocessable, options)
+	case "query_canceled":
+		return wrap(err, tiny_errors.ERR_CODE_Timeout, http.StatusGatewayTimeout, tiny_errors.ErrTimeout, options)
+	case "too_many_connections", "admin_shutdown", "crash_shutdown", "cannot_connect_now":
+		return wrap(err, tiny_errors.ERR_CODE_Unavailable, http.StatusServiceUnavailable, tiny_errors.ErrUnavailable, options)
+	}
+	if pqErr.Code.Class() == "08" {
+		return wrap(err, tiny_errors.ERR_CODE_Unavailable, http.StatusServiceUnavailable, tiny_errors.ErrUnavailable, options)
+	}
+	return nil
+}
+
+func wrap(err error, code int, status int, message string, options []tiny_errors.ErrorOption) tiny_errors.ErrorHandler {
+	options = append(options, tiny_errors.HTTPStatus(status), tiny_errors.Message(message))
+	return tiny_errors.Wrap(err, code, options...)
+}
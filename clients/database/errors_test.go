@@ -0,0 +1,53 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/Moranilt/http-utils/tiny_errors"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTranslatePQ(t *testing.T) {
+	tests := []struct {
+		name    string
+		code    pq.ErrorCode
+		errCode int
+		status  int
+	}{
+		{"unique violation", "23505", tiny_errors.ERR_CODE_Conflict, http.StatusConflict},
+		{"deadlock", "40P01", tiny_errors.ERR_CODE_Conflict, http.StatusConflict},
+		{"foreign key violation", "23503", tiny_errors.ERR_CODE_Unprocessable, http.StatusUnprocessableEntity},
+		{"not null violation", "23502", tiny_errors.ERR_CODE_Unprocessable, http.StatusUnprocessableEntity},
+		{"query canceled", "57014", tiny_errors.ERR_CODE_Timeout, http.StatusGatewayTimeout},
+		{"connection failure", "08006", tiny_errors.ERR_CODE_Unavailable, http.StatusServiceUnavailable},
+		{"too many connections", "53300", tiny_errors.ERR_CODE_Unavailable, http.StatusServiceUnavailable},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pqErr := &pq.Error{Code: test.code, Message: "pq error"}
+			err := tiny_errors.Translate(fmt.Errorf("create user: %w", pqErr))
+
+			assert.Equal(t, test.errCode, err.GetCode())
+			assert.Equal(t, test.status, err.GetHTTPStatus())
+			assert.True(t, errors.Is(err, pqErr))
+		})
+	}
+}
+
+func TestTranslatePQConstraint(t *testing.T) {
+	err := TranslatePQ(&pq.Error{Code: "23505", Constraint: "users_email_key"})
+
+	assert.Equal(t, tiny_errors.ERR_CODE_Conflict, err.GetCode())
+	assert.Equal(t, tiny_errors.ErrConflict, err.GetMessage())
+	assert.Equal(t, map[string]any{"constraint": "users_email_key"}, err.GetDetails())
+}
+
+func TestTranslatePQUnsupported(t *testing.T) {
+	assert.Nil(t, TranslatePQ(errors.New("boom")))
+	assert.Nil(t, TranslatePQ(&pq.Error{Code: "42601"}))
+}
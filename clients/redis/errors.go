@@ -0,0 +1,22 @@
+package redis
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Moranilt/http-utils/tiny_errors"
+	"github.com/redis/go-redis/v9"
+)
+
+func init() {
+	tiny_errors.RegisterTranslator(TranslateNil)
+}
+
+// Translate redis.Nil reply into ERR_CODE_NotFound (404).
+// Registered in tiny_errors by importing the package.
+func TranslateNil(err error) tiny_errors.ErrorHandler {
+	if errors.Is(err, redis.Nil) {
+		return tiny_errors.Wrap(err, tiny_errors.ERR_CODE_NotFound, tiny_errors.HTTPStatus(http.StatusNotFound), tiny_errors.Message(tiny_errors.ErrNotFound))
+	}
+	return nil
+}
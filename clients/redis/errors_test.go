@@ -0,0 +1,22 @@
+package redis
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/Moranilt/http-utils/tiny_errors"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTranslateNil(t *testing.T) {
+	err := tiny_errors.Translate(fmt.Errorf("get session: %w", redis.Nil))
+
+	assert.Equal(t, tiny_errors.ERR_CODE_NotFound, err.GetCode())
+	assert.Equal(t, http.StatusNotFound, err.GetHTTPStatus())
+	assert.True(t, errors.Is(err, redis.Nil))
+
+	assert.Nil(t, TranslateNil(errors.New("boom")))
+}
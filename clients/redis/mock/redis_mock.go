@@ -8,5 +8,5 @@ import (
 func New() (*redis.Client, redismock.ClientMock) {
 	client, mockClient := redismock.NewClientMock()
 
-	return &redis.Client{client}, mockClient
+	return &redis.Client{Client: client}, mockClient
 }
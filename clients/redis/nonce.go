@@ -0,0 +1,27 @@
+package redis
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// NonceStore keeps used nonces of signed requests in redis, so replayed
+// requests are rejected by every instance of the service.
+type NonceStore struct {
+	client redis.Cmdable
+	prefix string
+}
+
+// Create new signing.NonceStore using redis client. prefix is added to every key
+func NewNonceStore(client redis.Cmdable, prefix string) *NonceStore {
+	return &NonceStore{
+		client: client,
+		prefix: prefix,
+	}
+}
+
+func (s *NonceStore) Add(ctx context.Context, nonce string, ttl time.Duration) (bool, error) {
+	return s.client.SetNX(ctx, s.prefix+nonce, 1, ttl).Result()
+}
@@ -0,0 +1,32 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redismock/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNonceStore_Add(t *testing.T) {
+	client, mock := redismock.NewClientMock()
+	store := NewNonceStore(client, "nonce:")
+
+	mock.ExpectSetNX("nonce:key:1", 1, time.Minute).SetVal(true)
+	mock.ExpectSetNX("nonce:key:1", 1, time.Minute).SetVal(false)
+	mock.ExpectSetNX("nonce:key:2", 1, time.Minute).SetErr(errors.New("connection refused"))
+
+	added, err := store.Add(context.Background(), "key:1", time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, added)
+
+	added, err = store.Add(context.Background(), "key:1", time.Minute)
+	assert.NoError(t, err)
+	assert.False(t, added)
+
+	_, err = store.Add(context.Background(), "key:2", time.Minute)
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,148 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Moranilt/http-utils/ratelimit"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// KEYS[1] - key of the bucket
+// ARGV[1] - capacity, ARGV[2] - period in milliseconds, ARGV[3] - current time in milliseconds
+//
+// Returns {allowed, remaining, retry after ms, reset after ms}
+var tokenBucketScript = redis.NewScript(`
+local capacity = tonumber(ARGV[1])
+local period = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local perMs = capacity / period
+
+local data = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(data[1])
+local ts = tonumber(data[2])
+if tokens == nil or ts == nil then
+	tokens = capacity
+	ts = now
+end
+
+if now > ts then
+	tokens = math.min(capacity, tokens + (now - ts) * perMs)
+	ts = now
+end
+
+local allowed = 0
+local retry = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	retry = math.ceil((1 - tokens) / perMs)
+end
+
+local reset = math.ceil((capacity - tokens) / perMs)
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', ts)
+redis.call('PEXPIRE', KEYS[1], math.max(reset, 1))
+
+return {allowed, math.floor(tokens), retry, reset}
+`)
+
+// KEYS[1] - key of the window
+// ARGV[1] - limit, ARGV[2] - window in milliseconds, ARGV[3] - current time in milliseconds,
+// ARGV[4] - unique member of the request
+//
+// Returns {allowed, remaining, retry after ms, reset after ms}
+var slidingWindowScript = redis.NewScript(`
+local limit = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', now - window)
+local count = redis.call('ZCARD', KEYS[1])
+
+local allowed = 0
+local retry = 0
+if count < limit then
+	redis.call('ZADD', KEYS[1], now, ARGV[4])
+	count = count + 1
+	allowed = 1
+else
+	local oldest = redis.call('ZRANGE', KEYS[1], 0, 0, 'WITHSCORES')
+	retry = tonumber(oldest[2]) + window - now
+end
+
+local reset = 0
+local newest = redis.call('ZRANGE', KEYS[1], -1, -1, 'WITHSCORES')
+if newest[2] then
+	reset = tonumber(newest[2]) + window - now
+	redis.call('PEXPIRE', KEYS[1], math.max(reset, 1))
+end
+
+return {allowed, limit - count, retry, reset}
+`)
+
+// RateLimitStore keeps limits in redis. Every request is taken atomically by Lua script
+// so it can be shared between instances of the service.
+type RateLimitStore struct {
+	client redis.Scripter
+}
+
+// Create new ratelimit.Store using redis client
+func NewRateLimitStore(client redis.Scripter) *RateLimitStore {
+	return &RateLimitStore{
+		client: client,
+	}
+}
+
+func (s *RateLimitStore) TokenBucket(ctx context.Context, key string, rate ratelimit.Rate, now time.Time) (*ratelimit.Result, error) {
+	if err := validateRate(rate); err != nil {
+		return nil, err
+	}
+	values, err := tokenBucketScript.Run(ctx, s.client, []string{key},
+		rate.Limit, rate.Period.Milliseconds(), now.UnixMilli(),
+	).Int64Slice()
+	if err != nil {
+		return nil, err
+	}
+	return makeRateLimitResult(rate, values)
+}
+
+func (s *RateLimitStore) SlidingWindow(ctx context.Context, key string, rate ratelimit.Rate, now time.Time) (*ratelimit.Result, error) {
+	if err := validateRate(rate); err != nil {
+		return nil, err
+	}
+	values, err := slidingWindowScript.Run(ctx, s.client, []string{key},
+		rate.Limit, rate.Period.Milliseconds(), now.UnixMilli(), uuid.NewString(),
+	).Int64Slice()
+	if err != nil {
+		return nil, err
+	}
+	return makeRateLimitResult(rate, values)
+}
+
+// Scripts use milliseconds, so period should be at least 1ms
+func validateRate(rate ratelimit.Rate) error {
+	if err := rate.Validate(); err != nil {
+		return err
+	}
+	if rate.Period < time.Millisecond {
+		return fmt.Errorf("%w: period should be at least 1ms, got %s", ratelimit.ErrNotValidRate, rate.Period)
+	}
+	return nil
+}
+
+func makeRateLimitResult(rate ratelimit.Rate, values []int64) (*ratelimit.Result, error) {
+	if len(values) != 4 {
+		return nil, fmt.Errorf("unexpected rate limit script result: %v", values)
+	}
+	result := &ratelimit.Result{
+		Limit: rate.Limit,
+	}
+	result.Allowed = values[0] == 1
+	result.Remaining = int(values[1])
+	result.RetryAfter = time.Duration(values[2]) * time.Millisecond
+	result.ResetAfter = time.Duration(values[3]) * time.Millisecond
+	return result, nil
+}
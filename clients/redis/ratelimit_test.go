@@ -0,0 +1,56 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Moranilt/http-utils/ratelimit"
+	"github.com/go-redis/redismock/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimitStore_TokenBucket(t *testing.T) {
+	client, mock := redismock.NewClientMock()
+	store := NewRateLimitStore(client)
+	rate := ratelimit.Rate{Limit: 10, Period: time.Second}
+	now := time.UnixMilli(1700000000000)
+
+	mock.ExpectEvalSha(tokenBucketScript.Hash(), []string{"key"}, 10, int64(1000), now.UnixMilli()).
+		SetVal([]interface{}{int64(0), int64(0), int64(100), int64(1000)})
+
+	result, err := store.TokenBucket(context.Background(), "key", rate, now)
+	assert.NoError(t, err)
+	assert.Equal(t, &ratelimit.Result{
+		Allowed:    false,
+		Limit:      10,
+		Remaining:  0,
+		RetryAfter: 100 * time.Millisecond,
+		ResetAfter: time.Second,
+	}, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRateLimitStore_UnexpectedResult(t *testing.T) {
+	client, mock := redismock.NewClientMock()
+	store := NewRateLimitStore(client)
+	now := time.UnixMilli(1700000000000)
+
+	mock.ExpectEvalSha(tokenBucketScript.Hash(), []string{"key"}, 1, int64(1000), now.UnixMilli()).
+		SetVal([]interface{}{int64(1)})
+
+	_, err := store.TokenBucket(context.Background(), "key", ratelimit.Rate{Limit: 1, Period: time.Second}, now)
+	assert.Error(t, err)
+}
+
+func TestRateLimitStore_NotValidRate(t *testing.T) {
+	client, mock := redismock.NewClientMock()
+	store := NewRateLimitStore(client)
+	now := time.UnixMilli(1700000000000)
+
+	_, err := store.TokenBucket(context.Background(), "key", ratelimit.Rate{Limit: 0, Period: time.Second}, now)
+	assert.ErrorIs(t, err, ratelimit.ErrNotValidRate)
+	_, err = store.SlidingWindow(context.Background(), "key", ratelimit.Rate{Limit: 1, Period: time.Microsecond}, now)
+	assert.ErrorIs(t, err, ratelimit.ErrNotValidRate)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
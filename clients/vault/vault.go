@@ -2,6 +2,7 @@ package vault
 
 import (
 	"context"
+	"errors"
 
 	vault "github.com/hashicorp/vault/api"
 	"github.com/mitchellh/mapstructure"
@@ -43,6 +44,29 @@ func Init(cfg *Config) error {
 	return nil
 }
 
+// Check that vault is initialized and unsealed
+func (v *VaultClient) Check(ctx context.Context) error {
+	health, err := v.client.Sys().HealthWithContext(ctx)
+	if err != nil {
+		return err
+	}
+	if !health.Initialized {
+		return errors.New("vault is not initialized")
+	}
+	if health.Sealed {
+		return errors.New("vault is sealed")
+	}
+	return nil
+}
+
+// Check global vault client created by Init
+func Check(ctx context.Context) error {
+	if vaultClient == nil {
+		return errors.New("vault client is not initialized")
+	}
+	return vaultClient.Check(ctx)
+}
+
 func GetCreds[T any](ctx context.Context, secretPath string) (*T, error) {
 	kvSecret, err := vaultClient.client.KVv2(vaultClient.cfg.MountPath).Get(ctx, secretPath)
 	if err != nil {
@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/Moranilt/http-utils/jwt"
+	"github.com/mitchellh/mapstructure"
+)
+
+// Claim of the token can not be decoded into field of request
+var errInvalidClaim = errors.New("invalid claim")
+
+// Set fields with tag claim. Fields without tag are not changed,
+// so claims can't overwrite data from other sources of request.
+// Fields with tag are reset to zero value before binding, so values of
+// missing claims can't be sent by client in body or query.
+//
+// Returns error wrapping errInvalidClaim if value of claim does not fit type of field.
+// Other errors mean that type of request can not hold claims.
+func bindClaims(target any, claims jwt.Claims) error {
+	value := reflect.ValueOf(target).Elem()
+	if value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			value.Set(reflect.New(value.Type().Elem()))
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return fmt.Errorf("unable to bind claims into %s", value.Type())
+	}
+
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("claim"), ",")
+		if name == "" || !field.IsExported() {
+			continue
+		}
+
+		value.Field(i).SetZero()
+		claim, ok := claims[name]
+		if !ok {
+			continue
+		}
+
+		decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+			WeaklyTypedInput: true,
+			Result:           value.Field(i).Addr().Interface(),
+		})
+		if err != nil {
+			return err
+		}
+		if err := decoder.Decode(claim); err != nil {
+			return fmt.Errorf("%w %q: %w", errInvalidClaim, name, err)
+		}
+	}
+	return nil
+}
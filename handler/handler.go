@@ -3,13 +3,19 @@ package handler
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"mime/multipart"
 	"net/http"
+	"reflect"
 	"strings"
 
+	"github.com/Moranilt/http-utils/jwt"
 	"github.com/Moranilt/http-utils/logger"
+	"github.com/Moranilt/http-utils/modifiers"
+	"github.com/Moranilt/http-utils/ratelimit"
 	"github.com/Moranilt/http-utils/response"
 	"github.com/Moranilt/http-utils/tiny_errors"
+	"github.com/Moranilt/http-utils/validators"
 	"github.com/gorilla/mux"
 	"github.com/mitchellh/mapstructure"
 )
@@ -23,6 +29,17 @@ const (
 	ERR_CODE_UnexpectedBody = 999
 )
 
+func init() {
+	tiny_errors.MustRegister(tiny_errors.Definition{
+		Code:        ERR_CODE_UnexpectedBody,
+		Name:        "UnexpectedBody",
+		Message:     "unable to unmarshal request body",
+		HTTPStatus:  http.StatusBadRequest,
+		Severity:    tiny_errors.SeverityWarning,
+		Description: "Request body, URL-query params or URI vars can not be parsed",
+	})
+}
+
 type HandlerMaker[ReqT any, RespT any] struct {
 	request     *http.Request
 	response    http.ResponseWriter
@@ -30,6 +47,7 @@ type HandlerMaker[ReqT any, RespT any] struct {
 	logger      logger.Logger
 	caller      CallerFunc[ReqT, RespT]
 	err         tiny_errors.ErrorHandler
+	options     []response.Option
 }
 
 // A function that is called to process request.
@@ -41,8 +59,11 @@ type CallerFunc[ReqT any, RespT any] func(ctx context.Context, req ReqT) (RespT,
 // Create new handler instance
 //
 // **caller** should be a function that implements type CallerFunc[ReqT, RespT]
+//
+// Context of the request contains container of meta, so caller can add meta to response by response.AddMeta
 func New[ReqT any, RespT any](w http.ResponseWriter, r *http.Request, logger logger.Logger, caller CallerFunc[ReqT, RespT]) *HandlerMaker[ReqT, RespT] {
 	log := logger.WithRequestInfo(r)
+	r = r.WithContext(response.ContextWithMeta(r.Context()))
 	return &HandlerMaker[ReqT, RespT]{
 		logger:   log,
 		request:  r,
@@ -278,20 +299,180 @@ func (h *HandlerMaker[ReqT, RespT]) WithMultipart(maxMemory int64) *HandlerMaker
 	return h
 }
 
-// Run handler and send response with status code
+// Binding claims of JWT from request context.
+//
+// Claims are stored in context by jwt.Verifier.Middleware. If there are no claims in context
+// or a claim can not be decoded into its field, handler responds with status 401.
+// If request type is not a struct, handler responds with status 500.
+//
+// Request type should include fields with tags of claim.
+//
+// Example:
+//
+//	type YourRequest struct {
+//			UserID string `claim:"sub"`
+//			Email  string `claim:"email"`
+//	}
+func (h *HandlerMaker[ReqT, RespT]) WithClaims() *HandlerMaker[ReqT, RespT] {
+	if h.err != nil {
+		return h
+	}
+	claims, ok := jwt.ClaimsFromContext(h.request.Context())
+	if !ok {
+		h.err = jwt.Unauthorized(jwt.ErrMissingToken)
+		return h
+	}
+
+	err := bindClaims(&h.requestBody, claims)
+	if errors.Is(err, errInvalidClaim) {
+		h.err = tiny_errors.Wrap(err, jwt.ERR_CODE_Unauthorized, tiny_errors.Message(jwt.ErrInvalidToken), tiny_errors.HTTPStatus(http.StatusUnauthorized))
+		return h
+	}
+	if err != nil {
+		h.err = tiny_errors.Translate(err)
+		return h
+	}
+
+	return h
+}
+
+// Limit requests using limiter.
+//
+// If key is nil, key of the limiter is used. Otherwise key is taken from the
+// request body, so this step should be called after parsing of the request.
+//
+// Example:
+//
+//	handler.New(w, r, log, caller).
+//		WithJSON().
+//		WithRateLimit(limiter, func(req YourRequest) string {
+//			return req.UserID
+//		}).
+//		Run(http.StatusOK)
+func (h *HandlerMaker[ReqT, RespT]) WithRateLimit(limiter *ratelimit.Limiter, key func(req ReqT) string) *HandlerMaker[ReqT, RespT] {
+	if h.err != nil {
+		return h
+	}
+
+	var limiterKey string
+	if key != nil {
+		limiterKey = key(h.requestBody)
+	} else {
+		limiterKey = limiter.Key(h.request)
+	}
+
+	if err := limiter.Check(h.request.Context(), h.response, limiterKey); err != nil {
+		h.err = err
+	}
+	return h
+}
+
+// Modify request body by modifiers of "mod" tags (see modifiers.Struct), e.g. trim spaces or set default values.
+// This step should be called after parsing of the request and before validation.
+//
+// Example:
+//
+//	type CreateUserRequest struct {
+//		Email string `json:"email" mod:"trim,lower" validate:"required,email"`
+//		Limit int    `json:"limit" mod:"default=10" validate:"max=100"`
+//	}
+//
+//	handler.New(w, r, log, caller).
+//		WithJSON().
+//		WithModifiers().
+//		WithValidation().
+//		Run(http.StatusCreated)
+func (h *HandlerMaker[ReqT, RespT]) WithModifiers() *HandlerMaker[ReqT, RespT] {
+	if h.err != nil {
+		return h
+	}
+	modifiers.Struct(&h.requestBody)
+	return h
+}
+
+// Validate request body by rules of "validate" tags (see validators.Struct).
+// If request is not valid, handler responds with tiny_errors.FieldErrors and status 400.
+// This step should be called after parsing of the request.
+//
+// Example:
+//
+//	type CreateUserRequest struct {
+//		Name string `json:"name" validate:"required,max=64"`
+//	}
+//
+//	handler.New(w, r, log, caller).
+//		WithJSON().
+//		WithValidation().
+//		Run(http.StatusCreated)
+func (h *HandlerMaker[ReqT, RespT]) WithValidation() *HandlerMaker[ReqT, RespT] {
+	if h.err != nil {
+		return h
+	}
+	if err := validators.Struct(h.requestBody); err != nil {
+		h.err = err
+	}
+	return h
+}
+
+// Send only fields of response body listed in URL-query param "fields", e.g. "?fields=id,name,owner.email".
+//
+// Nested fields are separated by dot, fields of arrays are applied to every item.
+// Names of fields are checked against json tags of response type. If any field is unknown,
+// handler responds with status 400.
+func (h *HandlerMaker[ReqT, RespT]) WithFields() *HandlerMaker[ReqT, RespT] {
+	if h.err != nil {
+		return h
+	}
+	fields, err := response.FieldsFromRequest(h.request, reflect.TypeFor[RespT]())
+	if err != nil {
+		h.err = err
+		return h
+	}
+	if fields != nil {
+		h.options = append(h.options, response.WithFields(fields))
+	}
+	return h
+}
+
+// Set options of response for this handler. Options are applied after defaults set by response.SetDefaults
+//
+// Example:
+//
+//	handler.New(w, r, log, caller).
+//		WithResponseOptions(response.WithEnvelope(response.BareEnvelope)).
+//		Run(http.StatusOK)
+func (h *HandlerMaker[ReqT, RespT]) WithResponseOptions(options ...response.Option) *HandlerMaker[ReqT, RespT] {
+	h.options = append(h.options, options...)
+	return h
+}
+
+func (h *HandlerMaker[ReqT, RespT]) responseOptions() []response.Option {
+	return append([]response.Option{response.Request(h.request), response.WithLogger(h.logger)}, h.options...)
+}
+
+// Run handler and send response with status code.
+//
+// If the client prefers CSV by header "Accept: text/csv" and response is a slice of structs,
+// it is sent as CSV (see response.CSV). Errors are always sent as JSON.
 func (h *HandlerMaker[ReqT, RespT]) Run(successStatus int) {
 	h.logger.With("body", h.requestBody).Info("request")
 	if h.err != nil {
-		h.logger.Error(h.err.Error(), "code", h.err.GetCode(), "details", h.err.GetDetails())
-		response.ErrorResponse(h.response, h.err, http.StatusBadRequest)
+		h.logger.Error(h.err.Error(), "error", tiny_errors.LogValue(h.err))
+		response.ErrorResponse(h.response, h.err, h.err.GetHTTPStatus(), h.responseOptions()...)
 		return
 	}
 
 	resp, err := h.caller(h.request.Context(), h.requestBody)
 	if err != nil {
-		h.logger.Error(err.Error(), "code", err.GetCode(), "details", err.GetDetails())
-		response.ErrorResponse(h.response, err, err.GetHTTPStatus())
+		h.logger.Error(err.Error(), "error", tiny_errors.LogValue(err))
+		response.ErrorResponse(h.response, err, err.GetHTTPStatus(), h.responseOptions()...)
 		return
 	}
-	response.SuccessResponse(h.response, resp, successStatus)
+	if response.WantsCSV(h.request) {
+		err := response.CSV(h.response, successStatus, resp, h.responseOptions()...)
+		if !errors.Is(err, response.ErrNotTabular) {
+			return
+		}
+	}
+	response.SuccessResponse(h.response, resp, successStatus, h.responseOptions()...)
 }
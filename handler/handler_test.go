@@ -12,11 +12,15 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/Moranilt/http-utils/jwt"
 	"github.com/Moranilt/http-utils/logger"
+	"github.com/Moranilt/http-utils/ratelimit"
 	"github.com/Moranilt/http-utils/response"
 	"github.com/Moranilt/http-utils/tiny_errors"
 	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
 )
 
 type mockRequest struct {
@@ -720,3 +724,365 @@ func mockedMultipartData(t testing.TB) *multipartData {
 		header: w.FormDataContentType(),
 	}
 }
+
+func TestHandlerWithRateLimit(t *testing.T) {
+	limiter := ratelimit.New(ratelimit.NewMemoryStore(), ratelimit.TokenBucket, ratelimit.Rate{Limit: 1, Period: time.Minute})
+	caller := makeMockedFunction(func(request mockRequest) *mockResponse {
+		return &mockResponse{Info: successInfo}
+	}, nil)
+
+	makeRequest := func(name string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(mockRequest{Name: name})
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(body))
+		rec := httptest.NewRecorder()
+		New(rec, req, logger.NewMock(), caller).
+			WithJSON().
+			WithRateLimit(limiter, func(req mockRequest) string {
+				return req.Name
+			}).
+			Run(http.StatusOK)
+		return rec
+	}
+
+	assert.Equal(t, http.StatusOK, makeRequest("John").Code)
+
+	rec := makeRequest("John")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "0", rec.Header().Get(ratelimit.HeaderRemaining))
+
+	var resp response.DefaultResponse[*mockResponse, *tiny_errors.Error]
+	err := json.NewDecoder(rec.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, ratelimit.ERR_CODE_TooManyRequests, resp.Error.Code)
+
+	assert.Equal(t, http.StatusOK, makeRequest("Elizabeth").Code)
+}
+
+type mockClaimsRequest struct {
+	Name   string   `json:"name"`
+	UserID string   `claim:"sub"`
+	Age    int      `claim:"age"`
+	Roles  []string `claim:"roles"`
+}
+
+func TestHandlerWithClaims(t *testing.T) {
+	var received mockClaimsRequest
+	caller := func(ctx context.Context, req mockClaimsRequest) (*mockResponse, tiny_errors.ErrorHandler) {
+		received = req
+		return &mockResponse{Info: successInfo}, nil
+	}
+
+	t.Run("claims in context", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":"John"}`))
+		req = req.WithContext(jwt.ContextWithClaims(req.Context(), jwt.Claims{
+			"sub":   "user-1",
+			"name":  "Elizabeth",
+			"age":   float64(30),
+			"roles": []any{"admin"},
+		}))
+		rec := httptest.NewRecorder()
+		New(rec, req, logger.NewMock(), caller).WithJSON().WithClaims().Run(http.StatusOK)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, mockClaimsRequest{
+			Name:   "John",
+			UserID: "user-1",
+			Age:    30,
+			Roles:  []string{"admin"},
+		}, received)
+	})
+
+	t.Run("claim fields in body are ignored", func(t *testing.T) {
+		body := `{"name":"John","UserID":"admin","Age":99,"Roles":["admin"]}`
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req = req.WithContext(jwt.ContextWithClaims(req.Context(), jwt.Claims{
+			"age": float64(30),
+		}))
+		rec := httptest.NewRecorder()
+		New(rec, req, logger.NewMock(), caller).WithJSON().WithClaims().Run(http.StatusOK)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, mockClaimsRequest{
+			Name: "John",
+			Age:  30,
+		}, received)
+	})
+
+	t.Run("no claims in context", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":"John"}`))
+		rec := httptest.NewRecorder()
+		New(rec, req, logger.NewMock(), caller).WithJSON().WithClaims().Run(http.StatusOK)
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+		var resp response.DefaultResponse[*mockResponse, *tiny_errors.Error]
+		err := json.NewDecoder(rec.Body).Decode(&resp)
+		assert.NoError(t, err)
+		assert.Equal(t, jwt.ERR_CODE_Unauthorized, resp.Error.Code)
+	})
+
+	t.Run("claim of wrong type", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":"John"}`))
+		req = req.WithContext(jwt.ContextWithClaims(req.Context(), jwt.Claims{
+			"age": map[string]any{"years": 30},
+		}))
+		rec := httptest.NewRecorder()
+		New(rec, req, logger.NewMock(), caller).WithJSON().WithClaims().Run(http.StatusOK)
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+		var resp response.DefaultResponse[*mockResponse, *tiny_errors.Error]
+		err := json.NewDecoder(rec.Body).Decode(&resp)
+		assert.NoError(t, err)
+		assert.Equal(t, jwt.ERR_CODE_Unauthorized, resp.Error.Code)
+		assert.Equal(t, jwt.ErrInvalidToken, resp.Error.Message)
+	})
+
+	t.Run("request type without fields", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req = req.WithContext(jwt.ContextWithClaims(req.Context(), jwt.Claims{"sub": "user-1"}))
+		rec := httptest.NewRecorder()
+		New(rec, req, logger.NewMock(), func(ctx context.Context, req string) (*mockResponse, tiny_errors.ErrorHandler) {
+			return &mockResponse{Info: successInfo}, nil
+		}).WithClaims().Run(http.StatusOK)
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+
+		var resp response.DefaultResponse[*mockResponse, *tiny_errors.Error]
+		err := json.NewDecoder(rec.Body).Decode(&resp)
+		assert.NoError(t, err)
+		assert.Equal(t, tiny_errors.ERR_CODE_Internal, resp.Error.Code)
+	})
+}
+
+func TestHandlerResponseMeta(t *testing.T) {
+	caller := func(ctx context.Context, req mockRequest) (*mockResponse, tiny_errors.ErrorHandler) {
+		response.AddMeta(ctx, "total", 10)
+		return &mockResponse{Info: successInfo}, nil
+	}
+
+	t.Run("meta of middleware and caller", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		ctx := response.ContextWithMeta(req.Context())
+		response.AddMeta(ctx, "region", "eu")
+		rec := httptest.NewRecorder()
+		New(rec, req.WithContext(ctx), logger.NewMock(), caller).Run(http.StatusOK)
+
+		var resp response.DefaultResponse[*mockResponse, *tiny_errors.Error]
+		err := json.NewDecoder(rec.Body).Decode(&resp)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]any{"region": "eu", "total": float64(10)}, resp.Meta)
+		assert.Equal(t, successInfo, resp.Body.Info)
+	})
+
+	t.Run("bare envelope", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		rec := httptest.NewRecorder()
+		New(rec, req, logger.NewMock(), caller).
+			WithResponseOptions(response.WithEnvelope(response.BareEnvelope)).
+			Run(http.StatusOK)
+
+		var resp mockResponse
+		err := json.NewDecoder(rec.Body).Decode(&resp)
+		assert.NoError(t, err)
+		assert.Equal(t, successInfo, resp.Info)
+	})
+}
+
+func TestHandlerCSV(t *testing.T) {
+	type row struct {
+		Id   int    `csv:"ID"`
+		Name string `json:"name"`
+	}
+
+	rowsCaller := func(ctx context.Context, req mockRequest) ([]row, tiny_errors.ErrorHandler) {
+		return []row{{Id: 1, Name: "John"}}, nil
+	}
+
+	t.Run("accept csv", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Accept", "text/csv")
+		rec := httptest.NewRecorder()
+		New(rec, req, logger.NewMock(), rowsCaller).Run(http.StatusOK)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "text/csv; charset=utf-8", rec.Header().Get("Content-Type"))
+		assert.Equal(t, "ID,name\n1,John\n", rec.Body.String())
+	})
+
+	t.Run("accept json", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		rec := httptest.NewRecorder()
+		New(rec, req, logger.NewMock(), rowsCaller).Run(http.StatusOK)
+
+		assert.JSONEq(t, `{"error":null,"body":[{"Id":1,"name":"John"}]}`, rec.Body.String())
+	})
+
+	t.Run("response is not tabular", func(t *testing.T) {
+		caller := func(ctx context.Context, req mockRequest) (*mockResponse, tiny_errors.ErrorHandler) {
+			return &mockResponse{Info: successInfo}, nil
+		}
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Accept", "text/csv")
+		rec := httptest.NewRecorder()
+		New(rec, req, logger.NewMock(), caller).Run(http.StatusOK)
+
+		assert.Equal(t, "application/json; charset=utf-8", rec.Header().Get("Content-Type"))
+	})
+}
+
+func TestHandlerWithFields(t *testing.T) {
+	type owner struct {
+		Email string `json:"email"`
+		Phone string `json:"phone"`
+	}
+	type item struct {
+		Id    int    `json:"id"`
+		Name  string `json:"name"`
+		Owner owner  `json:"owner"`
+	}
+
+	var called bool
+	caller := func(ctx context.Context, req mockRequest) ([]item, tiny_errors.ErrorHandler) {
+		called = true
+		return []item{{Id: 1, Name: "John", Owner: owner{Email: "john@example.com", Phone: "1"}}}, nil
+	}
+
+	t.Run("known fields", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/?fields=id,owner.email", nil)
+		rec := httptest.NewRecorder()
+		New(rec, req, logger.NewMock(), caller).WithFields().Run(http.StatusOK)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"error":null,"body":[{"id":1,"owner":{"email":"john@example.com"}}]}`, rec.Body.String())
+	})
+
+	t.Run("order of fields", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/?fields=owner,name", nil)
+		rec := httptest.NewRecorder()
+		New(rec, req, logger.NewMock(), caller).WithFields().Run(http.StatusOK)
+
+		assert.Equal(t, `{"error":null,"body":[{"name":"John","owner":{"email":"john@example.com","phone":"1"}}]}`+"\n", rec.Body.String())
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/?fields=name,id", nil)
+		req.Header.Set("Accept", "text/csv")
+		rec := httptest.NewRecorder()
+		New(rec, req, logger.NewMock(), caller).WithFields().Run(http.StatusOK)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "id,name\n1,John\n", rec.Body.String())
+	})
+
+	t.Run("unknown fields", func(t *testing.T) {
+		called = false
+		req := httptest.NewRequest(http.MethodGet, "/?fields=id,password", nil)
+		rec := httptest.NewRecorder()
+		New(rec, req, logger.NewMock(), caller).WithFields().Run(http.StatusOK)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.False(t, called)
+
+		var resp response.DefaultResponse[[]item, *tiny_errors.Error]
+		err := json.NewDecoder(rec.Body).Decode(&resp)
+		assert.NoError(t, err)
+		assert.Equal(t, response.ERR_CODE_UnknownFields, resp.Error.Code)
+	})
+
+	t.Run("not enabled", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/?fields=id", nil)
+		rec := httptest.NewRecorder()
+		New(rec, req, logger.NewMock(), caller).Run(http.StatusOK)
+
+		assert.JSONEq(t, `{"error":null,"body":[{"id":1,"name":"John","owner":{"email":"john@example.com","phone":"1"}}]}`, rec.Body.String())
+	})
+}
+
+func TestHandlerLogsStructuredError(t *testing.T) {
+	caller := func(ctx context.Context, req mockRequest) (*mockResponse, tiny_errors.ErrorHandler) {
+		return nil, tiny_errors.New(2000,
+			tiny_errors.Message("limit exceeded"),
+			tiny_errors.DetailValue("limit", 10),
+			tiny_errors.InternalDetail("account", "acc_1"),
+		)
+	}
+
+	var logs bytes.Buffer
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	New(rec, req, logger.New(&logs, logger.TYPE_JSON), caller).Run(http.StatusOK)
+
+	assert.JSONEq(t, `{"error":{"code":2000,"message":"limit exceeded","details":{"limit":10}},"body":null}`, rec.Body.String())
+	assert.Contains(t, logs.String(), `"error":{"code":2000,"message":"limit exceeded","http_status":400,"details":{"limit":10},"internal":{"account":"acc_1"}}`)
+}
+
+func TestHandlerWithValidation(t *testing.T) {
+	type request struct {
+		Name  string   `json:"name" validate:"required,max=8"`
+		Email string   `json:"email" validate:"required"`
+		Tags  []string `json:"tags" validate:"each(required)"`
+	}
+
+	var called bool
+	caller := func(ctx context.Context, req request) (*mockResponse, tiny_errors.ErrorHandler) {
+		called = true
+		return &mockResponse{}, nil
+	}
+
+	t.Run("not valid", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":"Johnathan Doe","tags":["a",""]}`))
+		rec := httptest.NewRecorder()
+		New(rec, req, logger.NewMock(), caller).WithJSON().WithValidation().Run(http.StatusOK)
+
+		assert.False(t, called)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.JSONEq(t, `{"error":{"code":980,"message":"validation failed","details":{"fields":[
+			{"field":"name","rule":"max","message":"must be at most 8 characters","params":{"max":8}},
+			{"field":"email","rule":"required","message":"is required"},
+			{"field":"tags[1]","rule":"required","message":"is required"}
+		]}},"body":null}`, rec.Body.String())
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":"John","email":"john@example.com"}`))
+		rec := httptest.NewRecorder()
+		New(rec, req, logger.NewMock(), caller).WithJSON().WithValidation().Run(http.StatusOK)
+
+		assert.True(t, called)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+}
+
+func TestHandlerWithModifiers(t *testing.T) {
+	type request struct {
+		Email string   `json:"email" mod:"trim,lower" validate:"required,email"`
+		Name  string   `json:"name" mod:"strip_html,collapse_spaces,trim" validate:"required"`
+		Tags  []string `json:"tags" mod:"trim,lower"`
+		Limit int      `json:"limit" mod:"default=10"`
+	}
+
+	var received request
+	caller := func(ctx context.Context, req request) (*mockResponse, tiny_errors.ErrorHandler) {
+		received = req
+		return &mockResponse{}, nil
+	}
+
+	t.Run("modified before validation", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"email":" Foo@Example.com ","name":"<b>John</b>  Doe ","tags":[" Go "]}`))
+		rec := httptest.NewRecorder()
+		New(rec, req, logger.NewMock(), caller).WithJSON().WithModifiers().WithValidation().Run(http.StatusOK)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, request{Email: "foo@example.com", Name: "John Doe", Tags: []string{"go"}, Limit: 10}, received)
+	})
+
+	t.Run("empty after modification", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"email":"john@example.com","name":" <br/> "}`))
+		rec := httptest.NewRecorder()
+		New(rec, req, logger.NewMock(), caller).WithJSON().WithModifiers().WithValidation().Run(http.StatusOK)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"field":"name","rule":"required"`)
+	})
+}
@@ -0,0 +1,350 @@
+// Package handlertest provides utilities to test HTTP handlers built with handler.HandlerMaker.
+//
+// Example:
+//
+//	func TestCreateUser(t *testing.T) {
+//		req := handlertest.NewRequest(t, http.MethodPost, "/users/{id}").
+//			WithVars(map[string]string{"id": "1"}).
+//			WithJSON(CreateUserRequest{Name: "John"})
+//
+//		resp := handlertest.Do[*CreateUserResponse](req, func(w http.ResponseWriter, r *http.Request, log logger.Logger) {
+//			handler.New(w, r, log, service.CreateUser).WithVars().WithJSON().Run(http.StatusCreated)
+//		})
+//
+//		resp.AssertStatus(http.StatusCreated).AssertNoError()
+//		assert.Equal(t, "John", resp.Body().Name)
+//	}
+package handlertest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+
+	"github.com/Moranilt/http-utils/logger"
+	"github.com/Moranilt/http-utils/response"
+	"github.com/Moranilt/http-utils/tiny_errors"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+// Function which runs handler. log writes to the buffer of Response
+type HandlerFunc func(w http.ResponseWriter, r *http.Request, log logger.Logger)
+
+type file struct {
+	field string
+	name  string
+	data  []byte
+}
+
+// Request builder
+type Request struct {
+	t       assert.TestingT
+	method  string
+	target  string
+	body    []byte
+	query   url.Values
+	vars    map[string]string
+	headers http.Header
+	fields  map[string]string
+	files   []file
+	ctx     context.Context
+}
+
+// Create new request builder
+func NewRequest(t assert.TestingT, method string, target string) *Request {
+	return &Request{
+		t:       t,
+		method:  method,
+		target:  target,
+		query:   make(url.Values),
+		headers: make(http.Header),
+		fields:  make(map[string]string),
+		ctx:     context.Background(),
+	}
+}
+
+// Set body of request encoded to JSON
+func (r *Request) WithJSON(body any) *Request {
+	data, err := json.Marshal(body)
+	if err != nil {
+		r.t.Errorf("unable to marshal request body: %v", err)
+		return r
+	}
+	r.body = data
+	r.headers.Set("Content-Type", "application/json")
+	return r
+}
+
+// Set raw body of request
+func (r *Request) WithBody(body []byte) *Request {
+	r.body = body
+	return r
+}
+
+// Add URL-query param
+func (r *Request) WithQuery(name, value string) *Request {
+	r.query.Add(name, value)
+	return r
+}
+
+// Set URI vars of gorilla/mux
+func (r *Request) WithVars(vars map[string]string) *Request {
+	r.vars = vars
+	return r
+}
+
+// Set header of request
+func (r *Request) WithHeader(name, value string) *Request {
+	r.headers.Set(name, value)
+	return r
+}
+
+// Add field of multipart form
+func (r *Request) WithField(name, value string) *Request {
+	r.fields[name] = value
+	return r
+}
+
+// Add file of multipart form. To add an array of files use field name with square brackets: "files[]"
+func (r *Request) WithFile(field, fileName string, data []byte) *Request {
+	r.files = append(r.files, file{field: field, name: fileName, data: data})
+	return r
+}
+
+// Set context of request
+func (r *Request) WithContext(ctx context.Context) *Request {
+	r.ctx = ctx
+	return r
+}
+
+// Build *http.Request
+func (r *Request) Build() *http.Request {
+	body := r.body
+	contentType := r.headers.Get("Content-Type")
+	if len(r.fields) > 0 || len(r.files) > 0 {
+		body, contentType = r.multipart()
+	}
+
+	req := httptest.NewRequest(r.method, r.target, bytes.NewReader(body))
+	req = req.WithContext(r.ctx)
+	for name, values := range r.headers {
+		req.Header[name] = values
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	if len(r.query) > 0 {
+		query := req.URL.Query()
+		for name, values := range r.query {
+			query[name] = append(query[name], values...)
+		}
+		req.URL.RawQuery = query.Encode()
+	}
+
+	if r.vars != nil {
+		req = mux.SetURLVars(req, r.vars)
+	}
+	return req
+}
+
+func (r *Request) multipart() ([]byte, string) {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	for name, value := range r.fields {
+		if err := w.WriteField(name, value); err != nil {
+			r.t.Errorf("unable to write form field %q: %v", name, err)
+		}
+	}
+	for _, f := range r.files {
+		fw, err := w.CreateFormFile(f.field, f.name)
+		if err != nil {
+			r.t.Errorf("unable to create form file %q: %v", f.field, err)
+			continue
+		}
+		fw.Write(f.data)
+	}
+	w.Close()
+	return body.Bytes(), w.FormDataContentType()
+}
+
+// Run handler with the request and decode response. Body is decoded only if Content-Type
+// of response is JSON, other bodies, e.g. CSV or NDJSON, are available in Response.Raw.
+// Body of application/problem+json is decoded into Response.Problem
+func Do[RespT any](r *Request, handler HandlerFunc) *Response[RespT] {
+	helper(r.t)
+
+	logs := new(bytes.Buffer)
+	rec := httptest.NewRecorder()
+	handler(rec, r.Build(), logger.New(logs, logger.TYPE_JSON))
+
+	resp := &Response[RespT]{
+		t:        r.t,
+		Recorder: rec,
+		Raw:      rec.Body.Bytes(),
+	}
+
+	contentType := rec.Header().Get("Content-Type")
+	switch {
+	case len(resp.Raw) == 0:
+	case isProblem(contentType):
+		resp.Problem = new(response.Problem)
+		if err := json.Unmarshal(resp.Raw, resp.Problem); err != nil {
+			r.t.Errorf("unable to decode problem details %q: %v", resp.Raw, err)
+		}
+		resp.problemErr = problemError(resp.Problem)
+	case isJSON(contentType):
+		if err := json.Unmarshal(resp.Raw, &resp.Envelope); err != nil {
+			r.t.Errorf("unable to decode response body %q: %v", resp.Raw, err)
+		}
+	}
+
+	decoder := json.NewDecoder(logs)
+	for {
+		var entry map[string]any
+		if err := decoder.Decode(&entry); err != nil {
+			if err != io.EOF {
+				r.t.Errorf("unable to decode log entry: %v", err)
+			}
+			break
+		}
+		resp.Logs = append(resp.Logs, entry)
+	}
+
+	return resp
+}
+
+// Media type is application/json or has suffix +json, e.g. application/problem+json
+func isJSON(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == response.ContentTypeJSON || strings.HasSuffix(mediaType, "+json")
+}
+
+// Media type is application/problem+json
+func isProblem(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && mediaType == response.ContentTypeProblemJSON
+}
+
+// Error of problem details. Code is taken from "code" member, other extension members become details
+func problemError(problem *response.Problem) *tiny_errors.Error {
+	var code int
+	options := []tiny_errors.ErrorOption{
+		tiny_errors.Message(problem.Detail),
+		tiny_errors.HTTPStatus(problem.Status),
+	}
+	for name, value := range problem.Extensions {
+		if name == "code" {
+			if number, ok := value.(float64); ok {
+				code = int(number)
+			}
+			continue
+		}
+		options = append(options, tiny_errors.DetailValue(name, value))
+	}
+	err, _ := tiny_errors.New(code, options...).(*tiny_errors.Error)
+	return err
+}
+
+// Decoded response of the handler
+type Response[RespT any] struct {
+	t assert.TestingT
+
+	Recorder *httptest.ResponseRecorder
+
+	// Raw body of response
+	Raw []byte
+
+	// Decoded body of response. Empty if response is not JSON or is problem details
+	Envelope response.DefaultResponse[RespT, *tiny_errors.Error]
+
+	// Decoded problem details. Nil if response is not application/problem+json
+	Problem *response.Problem
+
+	// Error converted from Problem
+	problemErr *tiny_errors.Error
+
+	// Log entries written by handler
+	Logs []map[string]any
+}
+
+// Body of response
+func (r *Response[RespT]) Body() RespT {
+	return r.Envelope.Body
+}
+
+// Error of response. Returns nil if there is no error.
+// For problem details code is taken from "code" member, message from "detail"
+// and details from other extension members
+func (r *Response[RespT]) Error() *tiny_errors.Error {
+	if r.Problem != nil {
+		return r.problemErr
+	}
+	return r.Envelope.Error
+}
+
+// Meta of response. Returns nil if there is no meta
+func (r *Response[RespT]) Meta() map[string]any {
+	return r.Envelope.Meta
+}
+
+// Check status code of response
+func (r *Response[RespT]) AssertStatus(status int) *Response[RespT] {
+	helper(r.t)
+	assert.Equal(r.t, status, r.Recorder.Code, "unexpected status code, body: %s", r.Raw)
+	return r
+}
+
+// Check body of response
+func (r *Response[RespT]) AssertBody(expected RespT) *Response[RespT] {
+	helper(r.t)
+	assert.Equal(r.t, expected, r.Envelope.Body)
+	return r
+}
+
+// Check that response has no error
+func (r *Response[RespT]) AssertNoError() *Response[RespT] {
+	helper(r.t)
+	assert.Nil(r.t, r.Error(), "unexpected error in response")
+	return r
+}
+
+// Check code of error in response
+func (r *Response[RespT]) AssertErrorCode(code int) *Response[RespT] {
+	helper(r.t)
+	err := r.Error()
+	if !assert.NotNil(r.t, err, "expected error with code %d in response", code) {
+		return r
+	}
+	assert.Equal(r.t, code, err.Code, "unexpected error code")
+	return r
+}
+
+// Check that handler logged a message with level. level is a name of logger level, e.g. "ERROR"
+func (r *Response[RespT]) AssertLogged(level, msg string) *Response[RespT] {
+	helper(r.t)
+	for _, entry := range r.Logs {
+		if entry["level"] == level && entry["msg"] == msg {
+			return r
+		}
+	}
+	assert.Fail(r.t, "log entry not found", "level %q, message %q, logs: %v", level, msg, r.Logs)
+	return r
+}
+
+func helper(t assert.TestingT) {
+	if h, ok := t.(interface{ Helper() }); ok {
+		h.Helper()
+	}
+}
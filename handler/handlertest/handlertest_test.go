@@ -0,0 +1,188 @@
+package handlertest
+
+import (
+	"context"
+	"mime/multipart"
+	"net/http"
+	"testing"
+
+	"github.com/Moranilt/http-utils/handler"
+	"github.com/Moranilt/http-utils/logger"
+	"github.com/Moranilt/http-utils/tiny_errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type testRequest struct {
+	ID    string `json:"id" mapstructure:"id"`
+	Name  string `json:"name" mapstructure:"name"`
+	Page  int    `json:"page" mapstructure:"page"`
+	Phone string `json:"phone" mapstructure:"phone"`
+}
+
+type testResponse struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Page int    `json:"page"`
+}
+
+type testMultipartRequest struct {
+	Name   string                  `mapstructure:"name"`
+	Avatar *multipart.FileHeader   `mapstructure:"avatar"`
+	Files  []*multipart.FileHeader `mapstructure:"files"`
+}
+
+const errCodeNameRequired = 1
+
+func testCaller(ctx context.Context, req testRequest) (*testResponse, tiny_errors.ErrorHandler) {
+	if req.Name == "" {
+		return nil, tiny_errors.New(errCodeNameRequired, tiny_errors.Message("name required"))
+	}
+	return &testResponse{ID: req.ID, Name: req.Name, Page: req.Page}, nil
+}
+
+func runTestCaller(w http.ResponseWriter, r *http.Request, log logger.Logger) {
+	handler.New(w, r, log, testCaller).WithJSON().WithVars().WithQuery().Run(http.StatusCreated)
+}
+
+func TestDo(t *testing.T) {
+	t.Run("success response", func(t *testing.T) {
+		req := NewRequest(t, http.MethodPost, "/users/{id}").
+			WithVars(map[string]string{"id": "1"}).
+			WithQuery("page", "2").
+			WithJSON(testRequest{Name: "John"})
+
+		Do[*testResponse](req, runTestCaller).
+			AssertStatus(http.StatusCreated).
+			AssertNoError().
+			AssertBody(&testResponse{ID: "1", Name: "John", Page: 2}).
+			AssertLogged("INFO", "request")
+	})
+
+	t.Run("error response", func(t *testing.T) {
+		req := NewRequest(t, http.MethodPost, "/users/{id}").
+			WithVars(map[string]string{"id": "1"}).
+			WithJSON(testRequest{})
+
+		resp := Do[*testResponse](req, runTestCaller).
+			AssertStatus(http.StatusBadRequest).
+			AssertErrorCode(errCodeNameRequired).
+			AssertLogged("ERROR", "name required")
+
+		assert.Nil(t, resp.Body())
+		assert.Equal(t, "name required", resp.Error().Message)
+	})
+
+	t.Run("request id in logs", func(t *testing.T) {
+		req := NewRequest(t, http.MethodPost, "/users").
+			WithContext(logger.ContextWithRequestId(context.Background(), "request-id")).
+			WithJSON(testRequest{Name: "John"})
+
+		resp := Do[*testResponse](req, runTestCaller)
+		if assert.NotEmpty(t, resp.Logs) {
+			assert.Equal(t, "request-id", resp.Logs[0]["request_id"])
+		}
+	})
+}
+
+func TestDo_NotJSON(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+	}{
+		{name: "csv", contentType: "text/csv; charset=utf-8", body: "id,name\n1,John\n"},
+		{name: "ndjson", contentType: "application/x-ndjson", body: "{\"id\":1}\n{\"id\":2}\n"},
+		{name: "empty", contentType: "", body: ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resp := Do[*testResponse](NewRequest(t, http.MethodGet, "/users"), func(w http.ResponseWriter, r *http.Request, log logger.Logger) {
+				if test.contentType != "" {
+					w.Header().Set("Content-Type", test.contentType)
+				}
+				w.Write([]byte(test.body))
+			})
+
+			resp.AssertStatus(http.StatusOK)
+			assert.Equal(t, test.body, string(resp.Raw))
+			assert.Nil(t, resp.Body())
+		})
+	}
+}
+
+func TestDo_Problem(t *testing.T) {
+	req := NewRequest(t, http.MethodPost, "/users").
+		WithHeader("Accept", "application/problem+json").
+		WithJSON(testRequest{})
+	resp := Do[*testResponse](req, runTestCaller)
+
+	resp.AssertStatus(http.StatusBadRequest).AssertErrorCode(errCodeNameRequired)
+	if assert.NotNil(t, resp.Problem) {
+		assert.Equal(t, "name required", resp.Problem.Detail)
+		assert.Equal(t, http.StatusBadRequest, resp.Problem.Status)
+	}
+	if assert.NotNil(t, resp.Error()) {
+		assert.Equal(t, "name required", resp.Error().GetMessage())
+		assert.Equal(t, http.StatusBadRequest, resp.Error().GetHTTPStatus())
+	}
+	assert.Nil(t, resp.Body())
+
+	rt := &recordingT{}
+	Do[*testResponse](NewRequest(rt, http.MethodPost, "/users").
+		WithHeader("Accept", "application/problem+json").
+		WithJSON(testRequest{}), runTestCaller).AssertNoError()
+	assert.True(t, rt.failed)
+}
+
+func TestDo_Multipart(t *testing.T) {
+	var received testMultipartRequest
+	caller := func(ctx context.Context, req testMultipartRequest) (string, tiny_errors.ErrorHandler) {
+		received = req
+		return "ok", nil
+	}
+
+	req := NewRequest(t, http.MethodPost, "/upload").
+		WithField("name", "John").
+		WithFile("avatar", "avatar.png", []byte("png")).
+		WithFile("files[]", "first.txt", []byte("first")).
+		WithFile("files[]", "second.txt", []byte("second")).
+		WithHeader("X-Custom", "value")
+
+	Do[string](req, func(w http.ResponseWriter, r *http.Request, log logger.Logger) {
+		assert.Equal(t, "value", r.Header.Get("X-Custom"))
+		handler.New(w, r, log, caller).WithMultipart(32 << 20).Run(http.StatusOK)
+	}).AssertStatus(http.StatusOK).AssertBody("ok")
+
+	assert.Equal(t, "John", received.Name)
+	if assert.NotNil(t, received.Avatar) {
+		assert.Equal(t, "avatar.png", received.Avatar.Filename)
+	}
+	assert.Len(t, received.Files, 2)
+}
+
+type recordingT struct {
+	failed bool
+}
+
+func (r *recordingT) Errorf(format string, args ...any) {
+	r.failed = true
+}
+
+func TestResponse_Assertions(t *testing.T) {
+	rt := &recordingT{}
+	req := NewRequest(rt, http.MethodPost, "/users").WithJSON(testRequest{Name: "John"})
+	resp := Do[*testResponse](req, runTestCaller)
+	assert.False(t, rt.failed)
+
+	resp.AssertStatus(http.StatusOK)
+	assert.True(t, rt.failed)
+
+	rt.failed = false
+	resp.AssertErrorCode(errCodeNameRequired)
+	assert.True(t, rt.failed)
+
+	rt.failed = false
+	resp.AssertLogged("ERROR", "request")
+	assert.True(t, rt.failed)
+}
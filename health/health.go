@@ -0,0 +1,350 @@
+package health
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Moranilt/http-utils/clients/vault"
+	"github.com/Moranilt/http-utils/logger"
+	"github.com/Moranilt/http-utils/response"
+	"github.com/Moranilt/http-utils/tiny_errors"
+)
+
+const (
+	ERR_CODE_NotReady = 993
+	ERR_CODE_NotAlive = 992
+)
+
+const (
+	ErrNotReady    = "service is not ready"
+	ErrNotAlive    = "service is not alive"
+	ErrCheckFailed = "health check is failed"
+)
+
+func init() {
+	tiny_errors.MustRegister(
+		tiny_errors.Definition{
+			Code:        ERR_CODE_NotReady,
+			Name:        "NotReady",
+			Message:     ErrNotReady,
+			HTTPStatus:  http.StatusServiceUnavailable,
+			Severity:    tiny_errors.SeverityCritical,
+			Description: "Critical dependency of the service is not available or the service is shutting down",
+		},
+		tiny_errors.Definition{
+			Code:        ERR_CODE_NotAlive,
+			Name:        "NotAlive",
+			Message:     ErrNotAlive,
+			HTTPStatus:  http.StatusServiceUnavailable,
+			Severity:    tiny_errors.SeverityCritical,
+			Description: "Liveness check of the service is failed",
+		},
+	)
+}
+
+type Status string
+
+const (
+	StatusOK Status = "ok"
+
+	// Only non-critical checks are failed
+	StatusDegraded Status = "degraded"
+	StatusFail     Status = "fail"
+)
+
+const defaultTimeout = 5 * time.Second
+
+// Checker checks state of dependency.
+//
+// database.Client, redis.Client and rabbitmq.RabbitMQClient implement this interface.
+type Checker interface {
+	Check(ctx context.Context) error
+}
+
+// CheckerFunc is an adapter to use functions as Checker
+type CheckerFunc func(ctx context.Context) error
+
+func (f CheckerFunc) Check(ctx context.Context) error {
+	return f(ctx)
+}
+
+// Checker of vault client created by vault.Init
+func Vault() Checker {
+	return CheckerFunc(vault.Check)
+}
+
+// Result of one check.
+//
+// Error is sent by Livez and Readyz only if Health is created with ExposeErrors option,
+// because errors of dependencies may contain addresses and credentials.
+type CheckResult struct {
+	Status    Status    `json:"status"`
+	Critical  bool      `json:"critical"`
+	Duration  string    `json:"duration"`
+	Error     string    `json:"error,omitempty"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// Result of all checks
+type Report struct {
+	Status Status                  `json:"status"`
+	Checks map[string]*CheckResult `json:"checks"`
+}
+
+type check struct {
+	name     string
+	checker  Checker
+	timeout  time.Duration
+	critical bool
+	liveness bool
+	cacheTTL time.Duration
+	logger   logger.Logger
+
+	mu     sync.Mutex
+	cached *CheckResult
+}
+
+// CheckOption is a function type that can be used to configure a registered check.
+type CheckOption func(*check)
+
+// Set timeout of the check. Default is 5 seconds
+func Timeout(timeout time.Duration) CheckOption {
+	return func(c *check) {
+		c.timeout = timeout
+	}
+}
+
+// Failure of non-critical check makes status degraded, but service is still ready
+func NonCritical() CheckOption {
+	return func(c *check) {
+		c.critical = false
+	}
+}
+
+// Cache result of the check for ttl
+func Cache(ttl time.Duration) CheckOption {
+	return func(c *check) {
+		c.cacheTTL = ttl
+	}
+}
+
+// Run the check in liveness probe too. Should be used only for checks
+// which can be fixed by restart of the service.
+func Liveness() CheckOption {
+	return func(c *check) {
+		c.liveness = true
+	}
+}
+
+type Health struct {
+	checks       []*check
+	shuttingDown bool
+	logger       logger.Logger
+	exposeErrors bool
+	mu           sync.RWMutex
+}
+
+// Option is a function type that can be used to configure Health.
+type Option func(*Health)
+
+// Set logger of failed checks. Default is logger.Default()
+func WithLogger(log logger.Logger) Option {
+	return func(h *Health) {
+		h.logger = log
+	}
+}
+
+// Send errors of failed checks in responses of Livez and Readyz. By default errors
+// are only logged, because probes are usually not authenticated
+func ExposeErrors() Option {
+	return func(h *Health) {
+		h.exposeErrors = true
+	}
+}
+
+// Create new Health
+//
+// Example:
+//
+//	h := health.New()
+//	h.Register("postgres", db, health.Timeout(2*time.Second))
+//	h.Register("redis", redisClient, health.NonCritical(), health.Cache(5*time.Second))
+//	h.Register("vault", health.Vault())
+//	router.HandleFunc("/livez", h.Livez)
+//	router.HandleFunc("/readyz", h.Readyz)
+func New(options ...Option) *Health {
+	h := &Health{
+		logger: logger.Default(),
+	}
+	for _, opt := range options {
+		opt(h)
+	}
+	return h
+}
+
+// Register checker with name. Checks are critical by default.
+func (h *Health) Register(name string, checker Checker, options ...CheckOption) {
+	c := &check{
+		name:     name,
+		checker:  checker,
+		timeout:  defaultTimeout,
+		critical: true,
+		logger:   h.logger,
+	}
+	for _, opt := range options {
+		opt(c)
+	}
+
+	h.mu.Lock()
+	h.checks = append(h.checks, c)
+	h.mu.Unlock()
+}
+
+// Make readiness probe fail. Used during graceful shutdown to stop receiving new requests
+func (h *Health) SetShuttingDown() {
+	h.mu.Lock()
+	h.shuttingDown = true
+	h.mu.Unlock()
+}
+
+// Run all checks concurrently
+func (h *Health) Ready(ctx context.Context) *Report {
+	h.mu.RLock()
+	checks := h.checks
+	shuttingDown := h.shuttingDown
+	h.mu.RUnlock()
+
+	report := run(ctx, checks)
+	if shuttingDown {
+		report.Status = StatusFail
+	}
+	return report
+}
+
+// Run checks registered with Liveness option concurrently
+func (h *Health) Live(ctx context.Context) *Report {
+	h.mu.RLock()
+	var checks []*check
+	for _, c := range h.checks {
+		if c.liveness {
+			checks = append(checks, c)
+		}
+	}
+	h.mu.RUnlock()
+
+	return run(ctx, checks)
+}
+
+// HTTP handler of liveness probe. Responds with status 503 if any critical check is failed.
+// Errors of checks are not sent without ExposeErrors option
+func (h *Health) Livez(w http.ResponseWriter, r *http.Request) {
+	h.writeReport(w, r, h.Live(r.Context()), ERR_CODE_NotAlive, ErrNotAlive)
+}
+
+// HTTP handler of readiness probe. Responds with status 503 if any critical check is failed.
+// Errors of checks are not sent without ExposeErrors option
+func (h *Health) Readyz(w http.ResponseWriter, r *http.Request) {
+	h.writeReport(w, r, h.Ready(r.Context()), ERR_CODE_NotReady, ErrNotReady)
+}
+
+func (h *Health) writeReport(w http.ResponseWriter, r *http.Request, report *Report, code int, message string) {
+	if !h.exposeErrors {
+		report = hideErrors(report)
+	}
+	if report.Status == StatusFail {
+		err := tiny_errors.New(code, tiny_errors.Message(message), tiny_errors.HTTPStatus(http.StatusServiceUnavailable))
+		response.Default(w, report, err, err.GetHTTPStatus(), response.Request(r))
+		return
+	}
+	response.SuccessResponse(w, report, http.StatusOK, response.Request(r))
+}
+
+// Copy of report without errors of checks. Cached results are shared, so they are not changed
+func hideErrors(report *Report) *Report {
+	hidden := &Report{
+		Status: report.Status,
+		Checks: make(map[string]*CheckResult, len(report.Checks)),
+	}
+	for name, result := range report.Checks {
+		copied := *result
+		copied.Error = ""
+		hidden.Checks[name] = &copied
+	}
+	return hidden
+}
+
+func run(ctx context.Context, checks []*check) *Report {
+	report := &Report{
+		Status: StatusOK,
+		Checks: make(map[string]*CheckResult, len(checks)),
+	}
+
+	results := make([]*CheckResult, len(checks))
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func(i int, c *check) {
+			defer wg.Done()
+			results[i] = c.run(ctx)
+		}(i, c)
+	}
+	wg.Wait()
+
+	for i, c := range checks {
+		result := results[i]
+		report.Checks[c.name] = result
+		if result.Status == StatusOK {
+			continue
+		}
+		if c.critical {
+			report.Status = StatusFail
+		} else if report.Status == StatusOK {
+			report.Status = StatusDegraded
+		}
+	}
+	return report
+}
+
+func (c *check) run(ctx context.Context) *CheckResult {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if c.cached != nil && now.Sub(c.cached.CheckedAt) < c.cacheTTL {
+		return c.cached
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- c.checker.Check(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-errCh:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	result := &CheckResult{
+		Status:    StatusOK,
+		Critical:  c.critical,
+		Duration:  time.Since(now).String(),
+		CheckedAt: now,
+	}
+	if err != nil {
+		result.Status = StatusFail
+		result.Error = err.Error()
+		c.logger.Error(ErrCheckFailed, "check", c.name, "critical", c.critical, "error", err.Error())
+	}
+
+	if c.cacheTTL > 0 {
+		c.cached = result
+	}
+	return result
+}
@@ -0,0 +1,209 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Moranilt/http-utils/clients/vault"
+	"github.com/Moranilt/http-utils/logger"
+	"github.com/Moranilt/http-utils/response"
+	"github.com/Moranilt/http-utils/tiny_errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func okChecker() Checker {
+	return CheckerFunc(func(ctx context.Context) error {
+		return nil
+	})
+}
+
+func failChecker(message string) Checker {
+	return CheckerFunc(func(ctx context.Context) error {
+		return errors.New(message)
+	})
+}
+
+func TestHealth_Readyz(t *testing.T) {
+	tests := []struct {
+		name           string
+		register       func(h *Health)
+		expectedStatus int
+		expectedReport Status
+	}{
+		{
+			name: "all checks are ok",
+			register: func(h *Health) {
+				h.Register("db", okChecker())
+				h.Register("redis", okChecker())
+			},
+			expectedStatus: http.StatusOK,
+			expectedReport: StatusOK,
+		},
+		{
+			name: "non-critical check is failed",
+			register: func(h *Health) {
+				h.Register("db", okChecker())
+				h.Register("redis", failChecker("connection refused"), NonCritical())
+			},
+			expectedStatus: http.StatusOK,
+			expectedReport: StatusDegraded,
+		},
+		{
+			name: "critical check is failed",
+			register: func(h *Health) {
+				h.Register("db", failChecker("connection refused"))
+				h.Register("redis", failChecker("connection refused"), NonCritical())
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedReport: StatusFail,
+		},
+		{
+			name: "check timeout",
+			register: func(h *Health) {
+				h.Register("db", CheckerFunc(func(ctx context.Context) error {
+					time.Sleep(time.Second)
+					return nil
+				}), Timeout(10*time.Millisecond))
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedReport: StatusFail,
+		},
+		{
+			name: "shutting down",
+			register: func(h *Health) {
+				h.Register("db", okChecker())
+				h.SetShuttingDown()
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedReport: StatusFail,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			h := New()
+			test.register(h)
+
+			rec := httptest.NewRecorder()
+			h.Readyz(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+			assert.Equal(t, test.expectedStatus, rec.Code)
+
+			var resp response.DefaultResponse[*Report, *tiny_errors.Error]
+			err := json.NewDecoder(rec.Body).Decode(&resp)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedReport, resp.Body.Status)
+			if test.expectedStatus == http.StatusServiceUnavailable {
+				assert.Equal(t, ERR_CODE_NotReady, resp.Error.Code)
+			} else {
+				assert.Nil(t, resp.Error)
+			}
+		})
+	}
+}
+
+func TestHealth_Report(t *testing.T) {
+	h := New()
+	h.Register("db", okChecker())
+	h.Register("redis", failChecker("connection refused"), NonCritical())
+
+	report := h.Ready(context.Background())
+	assert.Len(t, report.Checks, 2)
+	assert.Equal(t, StatusOK, report.Checks["db"].Status)
+	assert.True(t, report.Checks["db"].Critical)
+	assert.Equal(t, StatusFail, report.Checks["redis"].Status)
+	assert.Equal(t, "connection refused", report.Checks["redis"].Error)
+	assert.False(t, report.Checks["redis"].Critical)
+}
+
+func TestHealth_Errors(t *testing.T) {
+	readyz := func(h *Health) *Report {
+		h.Register("db", failChecker("dial tcp postgres.internal:5432: connection refused"))
+		rec := httptest.NewRecorder()
+		h.Readyz(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+		var resp response.DefaultResponse[*Report, *tiny_errors.Error]
+		err := json.NewDecoder(rec.Body).Decode(&resp)
+		assert.NoError(t, err)
+		return resp.Body
+	}
+
+	h := New(WithLogger(logger.NewMock()))
+	report := readyz(h)
+	assert.Equal(t, StatusFail, report.Checks["db"].Status)
+	assert.Empty(t, report.Checks["db"].Error)
+	assert.NotEmpty(t, h.Ready(context.Background()).Checks["db"].Error, "report of Ready should not be changed")
+
+	report = readyz(New(WithLogger(logger.NewMock()), ExposeErrors()))
+	assert.Equal(t, "dial tcp postgres.internal:5432: connection refused", report.Checks["db"].Error)
+}
+
+func TestHealth_Concurrent(t *testing.T) {
+	h := New()
+	for _, name := range []string{"first", "second", "third"} {
+		h.Register(name, CheckerFunc(func(ctx context.Context) error {
+			time.Sleep(100 * time.Millisecond)
+			return nil
+		}))
+	}
+
+	start := time.Now()
+	h.Ready(context.Background())
+	assert.Less(t, time.Since(start), 250*time.Millisecond)
+}
+
+func TestHealth_Cache(t *testing.T) {
+	var calls atomic.Int32
+	h := New()
+	h.Register("db", CheckerFunc(func(ctx context.Context) error {
+		calls.Add(1)
+		return nil
+	}), Cache(time.Minute))
+
+	h.Ready(context.Background())
+	h.Ready(context.Background())
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestHealth_Livez(t *testing.T) {
+	h := New()
+	h.Register("db", failChecker("connection refused"))
+	h.Register("deadlock", okChecker(), Liveness())
+
+	rec := httptest.NewRecorder()
+	h.Livez(rec, httptest.NewRequest(http.MethodGet, "/livez", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	report := h.Live(context.Background())
+	assert.Len(t, report.Checks, 1)
+	assert.Contains(t, report.Checks, "deadlock")
+
+	h.Register("broken", failChecker("deadlock"), Liveness())
+	rec = httptest.NewRecorder()
+	h.Livez(rec, httptest.NewRequest(http.MethodGet, "/livez", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+}
+
+func TestVault(t *testing.T) {
+	var sealed atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{
+			"initialized": true,
+			"sealed":      sealed.Load(),
+		})
+	}))
+	defer server.Close()
+
+	err := vault.Init(&vault.Config{Host: server.URL})
+	assert.NoError(t, err)
+
+	assert.NoError(t, Vault().Check(context.Background()))
+
+	sealed.Store(true)
+	assert.EqualError(t, Vault().Check(context.Background()), "vault is sealed")
+}
@@ -0,0 +1,290 @@
+package jwt
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	// Min interval between requests of JWKS
+	jwksMinRefreshInterval = 10 * time.Second
+
+	// Timeout of request of JWKS
+	jwksRequestTimeout = 10 * time.Second
+
+	// Max size of JWKS response body
+	maxJWKSBodySize = 1 << 20
+)
+
+// Key in JSON Web Key format
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+
+	// RSA
+	N string `json:"n"`
+	E string `json:"e"`
+
+	// EC
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+
+	// oct
+	K string `json:"k"`
+}
+
+// Parsed public key or secret of JWK
+func (j *JWK) Key() (any, error) {
+	switch j.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(j.N)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidKey, err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(j.E)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidKey, err)
+		}
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, nil
+	case "EC":
+		if j.Crv != "P-256" {
+			return nil, fmt.Errorf("%w: unsupported curve %q", ErrInvalidKey, j.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(j.X)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidKey, err)
+		}
+		y, err := base64.RawURLEncoding.DecodeString(j.Y)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidKey, err)
+		}
+		key := &ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}
+		if !key.Curve.IsOnCurve(key.X, key.Y) {
+			return nil, fmt.Errorf("%w: point is not on curve", ErrInvalidKey)
+		}
+		return key, nil
+	case "oct":
+		k, err := base64.RawURLEncoding.DecodeString(j.K)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidKey, err)
+		}
+		return k, nil
+	}
+	return nil, fmt.Errorf("%w: unsupported key type %q", ErrInvalidKey, j.Kty)
+}
+
+// Algorithm which can be used with the key
+func (j *JWK) algorithm() string {
+	if j.Alg != "" {
+		return j.Alg
+	}
+	switch j.Kty {
+	case "RSA":
+		return RS256
+	case "EC":
+		return ES256
+	case "oct":
+		return HS256
+	}
+	return ""
+}
+
+type jwkSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS is a KeyProvider which uses a set of JSON Web Keys.
+//
+// Keys loaded from URL are cached for ttl. If token has unknown kid, keys are
+// requested again. Keys are requested not often than once in 10 seconds, even if
+// request fails. Concurrent requests wait for one refresh, and if refresh fails,
+// keys loaded before are used.
+type JWKS struct {
+	load        func(ctx context.Context) ([]byte, error)
+	ttl         time.Duration
+	keys        []JWK
+	fetchedAt   time.Time
+	attemptedAt time.Time
+	err         error
+	refreshing  chan struct{}
+	mu          sync.Mutex
+}
+
+// Parse JWKS from JSON
+func ParseJWKS(data []byte) (*JWKS, error) {
+	keys, err := parseKeys(data)
+	if err != nil {
+		return nil, err
+	}
+	return &JWKS{keys: keys, fetchedAt: time.Now()}, nil
+}
+
+// Load JWKS from file
+func NewJWKSFromFile(path string) (*JWKS, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseJWKS(data)
+}
+
+// Create JWKS which is loaded from url and cached for ttl.
+//
+// If client is nil, http.Client with timeout of 10 seconds is used.
+func NewJWKSFromURL(url string, ttl time.Duration, client *http.Client) *JWKS {
+	if client == nil {
+		client = &http.Client{Timeout: jwksRequestTimeout}
+	}
+	return &JWKS{
+		ttl: ttl,
+		load: func(ctx context.Context) ([]byte, error) {
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+			if err != nil {
+				return nil, err
+			}
+			resp, err := client.Do(req)
+			if err != nil {
+				return nil, err
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				return nil, fmt.Errorf("unexpected status of JWKS response: %d", resp.StatusCode)
+			}
+			data, err := io.ReadAll(io.LimitReader(resp.Body, maxJWKSBodySize+1))
+			if err != nil {
+				return nil, err
+			}
+			if len(data) > maxJWKSBodySize {
+				return nil, fmt.Errorf("JWKS response is larger than %d bytes", maxJWKSBodySize)
+			}
+			return data, nil
+		},
+	}
+}
+
+func parseKeys(data []byte) ([]JWK, error) {
+	var set jwkSet
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("parse JWKS: %w", err)
+	}
+	return set.Keys, nil
+}
+
+func (j *JWKS) Key(ctx context.Context, kid string, alg string) (any, error) {
+	j.mu.Lock()
+	keys, fetchedAt := j.keys, j.fetchedAt
+	j.mu.Unlock()
+
+	var err error
+	if j.load != nil && (keys == nil || time.Since(fetchedAt) > j.ttl) {
+		keys, err = j.refresh(ctx)
+	}
+
+	key := find(keys, kid, alg)
+	if key == nil && j.load != nil && err == nil {
+		keys, err = j.refresh(ctx)
+		key = find(keys, kid, alg)
+	}
+
+	if key == nil {
+		if err != nil {
+			return nil, err
+		}
+		return nil, ErrKeyNotFound
+	}
+	return key.Key()
+}
+
+// Load keys if they were not requested in last 10 seconds and return current keys with error
+// of the last request. Keys are loaded without the lock, concurrent calls wait for the same request.
+func (j *JWKS) refresh(ctx context.Context) ([]JWK, error) {
+	j.mu.Lock()
+	if refreshing := j.refreshing; refreshing != nil {
+		keys := j.keys
+		j.mu.Unlock()
+		select {
+		case <-refreshing:
+		case <-ctx.Done():
+			return keys, ctx.Err()
+		}
+		j.mu.Lock()
+		defer j.mu.Unlock()
+		return j.keys, j.err
+	}
+
+	now := time.Now()
+	if now.Sub(j.attemptedAt) < jwksMinRefreshInterval {
+		defer j.mu.Unlock()
+		return j.keys, j.err
+	}
+	refreshing := make(chan struct{})
+	j.refreshing = refreshing
+	j.attemptedAt = now
+	j.mu.Unlock()
+
+	// Request is not canceled with ctx of the first caller, because other callers wait for it
+	loadCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), jwksRequestTimeout)
+	defer cancel()
+	data, err := j.load(loadCtx)
+	var keys []JWK
+	if err == nil {
+		keys, err = parseKeys(data)
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if err == nil {
+		j.keys = keys
+		j.fetchedAt = now
+	}
+	j.err = err
+	j.refreshing = nil
+	close(refreshing)
+	return j.keys, err
+}
+
+// Find key by kid. If kid is empty, the only key suitable for the algorithm is used
+func find(keys []JWK, kid string, alg string) *JWK {
+	var found *JWK
+	for i := range keys {
+		key := &keys[i]
+		if key.Use != "" && key.Use != "sig" {
+			continue
+		}
+		if key.algorithm() != alg {
+			continue
+		}
+		if kid != "" {
+			if key.Kid == kid {
+				return key
+			}
+			continue
+		}
+		if found != nil {
+			return nil
+		}
+		found = key
+	}
+	return found
+}
@@ -0,0 +1,190 @@
+package jwt
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func rsaJWK(kid string, key *rsa.PublicKey) JWK {
+	return JWK{
+		Kty: "RSA",
+		Kid: kid,
+		N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
+
+func ecJWK(kid string, key *ecdsa.PublicKey) JWK {
+	return JWK{
+		Kty: "EC",
+		Kid: kid,
+		Crv: "P-256",
+		X:   base64.RawURLEncoding.EncodeToString(key.X.Bytes()),
+		Y:   base64.RawURLEncoding.EncodeToString(key.Y.Bytes()),
+	}
+}
+
+func TestJWKS_File(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	data, _ := json.Marshal(jwkSet{Keys: []JWK{rsaJWK("rsa-1", &rsaKey.PublicKey), ecJWK("ec-1", &ecKey.PublicKey)}})
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	jwks, err := NewJWKSFromFile(path)
+	assert.NoError(t, err)
+
+	verifier := NewVerifier(Config{Keys: jwks, Now: func() time.Time { return testNow }})
+	claims := Claims{"sub": "user-1"}
+
+	_, err = verifier.Verify(context.Background(), signToken(t, RS256, "rsa-1", rsaKey, claims))
+	assert.NoError(t, err)
+	_, err = verifier.Verify(context.Background(), signToken(t, ES256, "ec-1", ecKey, claims))
+	assert.NoError(t, err)
+	_, err = verifier.Verify(context.Background(), signToken(t, ES256, "", ecKey, claims))
+	assert.NoError(t, err)
+	_, err = verifier.Verify(context.Background(), signToken(t, RS256, "unknown", rsaKey, claims))
+	assert.ErrorIs(t, err, ErrKeyNotFound)
+	_, err = verifier.Verify(context.Background(), signToken(t, RS256, "ec-1", rsaKey, claims))
+	assert.ErrorIs(t, err, ErrKeyNotFound)
+}
+
+func TestJWKS_URL(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		json.NewEncoder(w).Encode(jwkSet{Keys: []JWK{rsaJWK("rsa-1", &rsaKey.PublicKey)}})
+	}))
+	defer server.Close()
+
+	jwks := NewJWKSFromURL(server.URL, time.Hour, nil)
+	verifier := NewVerifier(Config{Keys: jwks, Now: func() time.Time { return testNow }})
+	token := signToken(t, RS256, "rsa-1", rsaKey, Claims{"sub": "user-1"})
+
+	for i := 0; i < 3; i++ {
+		_, err := verifier.Verify(context.Background(), token)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, int32(1), requests.Load())
+
+	_, err := verifier.Verify(context.Background(), signToken(t, RS256, "rsa-2", rsaKey, Claims{}))
+	assert.ErrorIs(t, err, ErrKeyNotFound)
+	assert.Equal(t, int32(1), requests.Load())
+}
+
+func TestJWKS_URLError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	jwks := NewJWKSFromURL(server.URL, time.Hour, nil)
+	_, err := jwks.Key(context.Background(), "kid", RS256)
+	assert.Error(t, err)
+}
+
+func TestJWKS_URLTooLarge(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"keys":[`))
+		w.Write(bytes.Repeat([]byte(" "), maxJWKSBodySize))
+		w.Write([]byte(`]}`))
+	}))
+	defer server.Close()
+
+	jwks := NewJWKSFromURL(server.URL, time.Hour, nil)
+	_, err := jwks.Key(context.Background(), "kid", RS256)
+	assert.ErrorContains(t, err, "JWKS response is larger")
+}
+
+func TestJWKS_URLErrorBackoff(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	jwks := NewJWKSFromURL(server.URL, time.Hour, nil)
+	for i := 0; i < 3; i++ {
+		_, err := jwks.Key(context.Background(), "kid", RS256)
+		assert.Error(t, err)
+	}
+	assert.Equal(t, int32(1), requests.Load())
+}
+
+func TestJWKS_URLStaleKeys(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	var failing atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		json.NewEncoder(w).Encode(jwkSet{Keys: []JWK{rsaJWK("rsa-1", &rsaKey.PublicKey)}})
+	}))
+	defer server.Close()
+
+	jwks := NewJWKSFromURL(server.URL, time.Minute, nil)
+	_, err := jwks.Key(context.Background(), "rsa-1", RS256)
+	assert.NoError(t, err)
+
+	failing.Store(true)
+	jwks.mu.Lock()
+	jwks.fetchedAt = time.Now().Add(-time.Hour)
+	jwks.attemptedAt = jwks.fetchedAt
+	jwks.mu.Unlock()
+
+	key, err := jwks.Key(context.Background(), "rsa-1", RS256)
+	assert.NoError(t, err)
+	assert.Equal(t, &rsaKey.PublicKey, key)
+	assert.Error(t, jwks.err)
+}
+
+func TestJWKS_URLConcurrentRefresh(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	var requests atomic.Int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		<-release
+		json.NewEncoder(w).Encode(jwkSet{Keys: []JWK{rsaJWK("rsa-1", &rsaKey.PublicKey)}})
+	}))
+	defer server.Close()
+
+	jwks := NewJWKSFromURL(server.URL, time.Hour, nil)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := jwks.Key(context.Background(), "rsa-1", RS256)
+			assert.NoError(t, err)
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), requests.Load())
+}
@@ -0,0 +1,365 @@
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Moranilt/http-utils/logger"
+)
+
+const (
+	HS256 = "HS256"
+	RS256 = "RS256"
+	ES256 = "ES256"
+)
+
+var (
+	ErrMalformed            = errors.New("malformed token")
+	ErrUnsupportedAlgorithm = errors.New("unsupported algorithm")
+	ErrInvalidSignature     = errors.New("invalid signature")
+	ErrKeyNotFound          = errors.New("key not found")
+	ErrInvalidKey           = errors.New("invalid key")
+	ErrExpired              = errors.New("token is expired")
+	ErrNotYetValid          = errors.New("token is not valid yet")
+	ErrInvalidIssuer        = errors.New("invalid issuer")
+	ErrInvalidAudience      = errors.New("invalid audience")
+	ErrKeysUnavailable      = errors.New("keys are unavailable")
+	ErrInvalidTimeClaim     = errors.New("invalid time claim")
+)
+
+// Range of NumericDate values: from 0000-01-01 to 9999-12-31 23:59:59 UTC
+const (
+	minNumericDate = -62167219200
+	maxNumericDate = 253402300799
+)
+
+// Claims of the token
+type Claims map[string]any
+
+// Returns "sub" claim
+func (c Claims) Subject() string {
+	return c.String("sub")
+}
+
+// Returns "iss" claim
+func (c Claims) Issuer() string {
+	return c.String("iss")
+}
+
+// Returns "aud" claim. Audience can be a string or an array of strings
+func (c Claims) Audience() []string {
+	switch aud := c["aud"].(type) {
+	case string:
+		return []string{aud}
+	case []string:
+		return aud
+	case []any:
+		result := make([]string, 0, len(aud))
+		for _, item := range aud {
+			if s, ok := item.(string); ok {
+				result = append(result, s)
+			}
+		}
+		return result
+	}
+	return nil
+}
+
+// Returns "exp" claim
+func (c Claims) ExpiresAt() (time.Time, bool) {
+	return c.Time("exp")
+}
+
+// Returns "nbf" claim
+func (c Claims) NotBefore() (time.Time, bool) {
+	return c.Time("nbf")
+}
+
+// Returns "iat" claim
+func (c Claims) IssuedAt() (time.Time, bool) {
+	return c.Time("iat")
+}
+
+// Returns string claim or empty string if claim is not a string
+func (c Claims) String(name string) string {
+	s, _ := c[name].(string)
+	return s
+}
+
+// Returns scopes from "scope" claim(space-delimited string) or "scp" claim(array of strings)
+func (c Claims) Scopes() []string {
+	if scope := c.String("scope"); scope != "" {
+		return strings.Fields(scope)
+	}
+	switch scp := c["scp"].(type) {
+	case string:
+		return strings.Fields(scp)
+	case []any:
+		result := make([]string, 0, len(scp))
+		for _, item := range scp {
+			if s, ok := item.(string); ok {
+				result = append(result, s)
+			}
+		}
+		return result
+	}
+	return nil
+}
+
+// Returns claim with NumericDate value. ok is false if claim is absent or is not a valid NumericDate
+func (c Claims) Time(name string) (time.Time, bool) {
+	t, present, err := c.numericDate(name)
+	return t, present && err == nil
+}
+
+// Parse claim with NumericDate value. Returns ErrInvalidTimeClaim if claim is present,
+// but is not a number of seconds between years 0 and 9999
+func (c Claims) numericDate(name string) (t time.Time, present bool, err error) {
+	value, ok := c[name]
+	if !ok {
+		return time.Time{}, false, nil
+	}
+
+	var seconds float64
+	switch v := value.(type) {
+	case float64:
+		seconds = v
+	case int64:
+		seconds = float64(v)
+	case int:
+		seconds = float64(v)
+	case json.Number:
+		seconds, err = v.Float64()
+		if err != nil {
+			return time.Time{}, true, fmt.Errorf("%w: %s", ErrInvalidTimeClaim, name)
+		}
+	default:
+		return time.Time{}, true, fmt.Errorf("%w: %s", ErrInvalidTimeClaim, name)
+	}
+	if math.IsNaN(seconds) || seconds < minNumericDate || seconds > maxNumericDate {
+		return time.Time{}, true, fmt.Errorf("%w: %s", ErrInvalidTimeClaim, name)
+	}
+
+	sec, frac := math.Modf(seconds)
+	return time.Unix(int64(sec), int64(frac*float64(time.Second))), true, nil
+}
+
+// KeyProvider returns a key to verify token signature.
+//
+// Returned key should be []byte for HS256, *rsa.PublicKey for RS256 and *ecdsa.PublicKey for ES256
+type KeyProvider interface {
+	Key(ctx context.Context, kid string, alg string) (any, error)
+}
+
+// KeyFunc is an adapter to use functions as KeyProvider
+type KeyFunc func(ctx context.Context, kid string, alg string) (any, error)
+
+func (f KeyFunc) Key(ctx context.Context, kid string, alg string) (any, error) {
+	return f(ctx, kid, alg)
+}
+
+// Use the same key for all tokens
+func StaticKey(key any) KeyProvider {
+	return KeyFunc(func(ctx context.Context, kid string, alg string) (any, error) {
+		return key, nil
+	})
+}
+
+type Config struct {
+	// Provider of keys. Required
+	Keys KeyProvider
+
+	// Allowed algorithms. Default is HS256, RS256 and ES256
+	Algorithms []string
+
+	// Expected "iss" claim. Not checked if empty
+	Issuer string
+
+	// Expected "aud" claim. Token should contain at least one of audiences. Not checked if empty
+	Audience []string
+
+	// Allowed difference of clocks when checking "exp" and "nbf"
+	ClockSkew time.Duration
+
+	// Require "exp" claim
+	RequireExpiration bool
+
+	// Returns current time. Default is time.Now
+	Now func() time.Time
+
+	// Logger to report why tokens are rejected in Middleware. Default is logger.Default()
+	Logger logger.Logger
+}
+
+type Verifier struct {
+	cfg Config
+}
+
+// Create new Verifier
+func NewVerifier(cfg Config) *Verifier {
+	if len(cfg.Algorithms) == 0 {
+		cfg.Algorithms = []string{HS256, RS256, ES256}
+	}
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = logger.Default()
+	}
+	return &Verifier{
+		cfg: cfg,
+	}
+}
+
+type header struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	Typ string `json:"typ"`
+}
+
+// Verify token signature and claims. Returns claims of the valid token
+func (v *Verifier) Verify(ctx context.Context, token string) (Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrMalformed
+	}
+
+	var h header
+	if err := decodeSegment(parts[0], &h); err != nil {
+		return nil, ErrMalformed
+	}
+	if !slices.Contains(v.cfg.Algorithms, h.Alg) {
+		return nil, ErrUnsupportedAlgorithm
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrMalformed
+	}
+
+	key, err := v.cfg.Keys.Key(ctx, h.Kid, h.Alg)
+	if err != nil {
+		if errors.Is(err, ErrKeyNotFound) || errors.Is(err, ErrInvalidKey) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %w", ErrKeysUnavailable, err)
+	}
+
+	if err := verifySignature(h.Alg, key, parts[0]+"."+parts[1], signature); err != nil {
+		return nil, err
+	}
+
+	var claims Claims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, ErrMalformed
+	}
+
+	if err := v.validate(claims); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+func (v *Verifier) validate(claims Claims) error {
+	now := v.cfg.Now()
+
+	exp, ok, err := claims.numericDate("exp")
+	if err != nil {
+		return err
+	}
+	if ok && !now.Before(exp.Add(v.cfg.ClockSkew)) {
+		return ErrExpired
+	}
+	if !ok && v.cfg.RequireExpiration {
+		return ErrExpired
+	}
+
+	nbf, ok, err := claims.numericDate("nbf")
+	if err != nil {
+		return err
+	}
+	if ok && now.Add(v.cfg.ClockSkew).Before(nbf) {
+		return ErrNotYetValid
+	}
+
+	if v.cfg.Issuer != "" && claims.Issuer() != v.cfg.Issuer {
+		return ErrInvalidIssuer
+	}
+
+	if len(v.cfg.Audience) > 0 {
+		audience := claims.Audience()
+		for _, aud := range v.cfg.Audience {
+			if slices.Contains(audience, aud) {
+				return nil
+			}
+		}
+		return ErrInvalidAudience
+	}
+
+	return nil
+}
+
+func verifySignature(alg string, key any, signingInput string, signature []byte) error {
+	hash := sha256.Sum256([]byte(signingInput))
+
+	switch alg {
+	case HS256:
+		secret, ok := key.([]byte)
+		if !ok {
+			return ErrInvalidKey
+		}
+		mac := hmac.New(sha256.New, secret)
+		mac.Write([]byte(signingInput))
+		if !hmac.Equal(mac.Sum(nil), signature) {
+			return ErrInvalidSignature
+		}
+	case RS256:
+		pub, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return ErrInvalidKey
+		}
+		if err := rsa.VerifyPKCS1v15(pub, crypto.SHA256, hash[:], signature); err != nil {
+			return ErrInvalidSignature
+		}
+	case ES256:
+		pub, ok := key.(*ecdsa.PublicKey)
+		if !ok {
+			return ErrInvalidKey
+		}
+		if len(signature) != 64 {
+			return ErrInvalidSignature
+		}
+		r := new(big.Int).SetBytes(signature[:32])
+		s := new(big.Int).SetBytes(signature[32:])
+		if !ecdsa.Verify(pub, hash[:], r, s) {
+			return ErrInvalidSignature
+		}
+	default:
+		return ErrUnsupportedAlgorithm
+	}
+	return nil
+}
+
+func decodeSegment(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("decode segment: %w", err)
+	}
+	return nil
+}
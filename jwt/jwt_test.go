@@ -0,0 +1,238 @@
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testNow = time.Unix(1700000000, 0)
+
+func signToken(t testing.TB, alg string, kid string, key any, claims Claims) string {
+	t.Helper()
+	h, _ := json.Marshal(header{Alg: alg, Kid: kid, Typ: "JWT"})
+	c, _ := json.Marshal(claims)
+	input := base64.RawURLEncoding.EncodeToString(h) + "." + base64.RawURLEncoding.EncodeToString(c)
+	hash := sha256.Sum256([]byte(input))
+
+	var signature []byte
+	switch alg {
+	case HS256:
+		mac := hmac.New(sha256.New, key.([]byte))
+		mac.Write([]byte(input))
+		signature = mac.Sum(nil)
+	case RS256:
+		sig, err := rsa.SignPKCS1v15(rand.Reader, key.(*rsa.PrivateKey), crypto.SHA256, hash[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		signature = sig
+	case ES256:
+		r, s, err := ecdsa.Sign(rand.Reader, key.(*ecdsa.PrivateKey), hash[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		signature = make([]byte, 64)
+		r.FillBytes(signature[:32])
+		s.FillBytes(signature[32:])
+	}
+	return input + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func TestVerifier_Algorithms(t *testing.T) {
+	secret := []byte("secret")
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	claims := Claims{"sub": "user-1", "exp": testNow.Add(time.Minute).Unix()}
+
+	tests := []struct {
+		name        string
+		token       string
+		key         any
+		algorithms  []string
+		expectedErr error
+	}{
+		{
+			name:  "HS256",
+			token: signToken(t, HS256, "", secret, claims),
+			key:   secret,
+		},
+		{
+			name:  "RS256",
+			token: signToken(t, RS256, "", rsaKey, claims),
+			key:   &rsaKey.PublicKey,
+		},
+		{
+			name:  "ES256",
+			token: signToken(t, ES256, "", ecKey, claims),
+			key:   &ecKey.PublicKey,
+		},
+		{
+			name:        "wrong secret",
+			token:       signToken(t, HS256, "", []byte("wrong"), claims),
+			key:         secret,
+			expectedErr: ErrInvalidSignature,
+		},
+		{
+			name:        "key of another algorithm",
+			token:       signToken(t, HS256, "", secret, claims),
+			key:         &rsaKey.PublicKey,
+			expectedErr: ErrInvalidKey,
+		},
+		{
+			name:        "not allowed algorithm",
+			token:       signToken(t, HS256, "", secret, claims),
+			key:         secret,
+			algorithms:  []string{RS256},
+			expectedErr: ErrUnsupportedAlgorithm,
+		},
+		{
+			name:        "malformed",
+			token:       "not.a-token",
+			key:         secret,
+			expectedErr: ErrMalformed,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			verifier := NewVerifier(Config{
+				Keys:       StaticKey(test.key),
+				Algorithms: test.algorithms,
+				Now:        func() time.Time { return testNow },
+			})
+			result, err := verifier.Verify(context.Background(), test.token)
+			if test.expectedErr != nil {
+				assert.ErrorIs(t, err, test.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "user-1", result.Subject())
+		})
+	}
+}
+
+func TestVerifier_Claims(t *testing.T) {
+	secret := []byte("secret")
+
+	tests := []struct {
+		name        string
+		claims      Claims
+		cfg         Config
+		expectedErr error
+	}{
+		{
+			name:   "valid claims",
+			claims: Claims{"exp": testNow.Add(time.Minute).Unix(), "nbf": testNow.Unix(), "iss": "auth", "aud": []string{"orders", "users"}},
+			cfg:    Config{Issuer: "auth", Audience: []string{"users"}},
+		},
+		{
+			name:        "expired",
+			claims:      Claims{"exp": testNow.Unix()},
+			expectedErr: ErrExpired,
+		},
+		{
+			name:   "expired within clock skew",
+			claims: Claims{"exp": testNow.Add(-time.Second).Unix()},
+			cfg:    Config{ClockSkew: 5 * time.Second},
+		},
+		{
+			name:        "expiration required",
+			claims:      Claims{},
+			cfg:         Config{RequireExpiration: true},
+			expectedErr: ErrExpired,
+		},
+		{
+			name:        "not valid yet",
+			claims:      Claims{"nbf": testNow.Add(time.Minute).Unix()},
+			expectedErr: ErrNotYetValid,
+		},
+		{
+			name:   "not valid yet within clock skew",
+			claims: Claims{"nbf": testNow.Add(time.Second).Unix()},
+			cfg:    Config{ClockSkew: 5 * time.Second},
+		},
+		{
+			name:        "expiration is not a number",
+			claims:      Claims{"exp": "tomorrow"},
+			expectedErr: ErrInvalidTimeClaim,
+		},
+		{
+			name:        "expiration out of range",
+			claims:      Claims{"exp": 1e300},
+			expectedErr: ErrInvalidTimeClaim,
+		},
+		{
+			name:        "not before is not a number",
+			claims:      Claims{"nbf": true},
+			expectedErr: ErrInvalidTimeClaim,
+		},
+		{
+			name:        "expiration in milliseconds",
+			claims:      Claims{"exp": testNow.Add(-time.Minute).UnixMilli()},
+			expectedErr: ErrInvalidTimeClaim,
+		},
+		{
+			name:        "invalid issuer",
+			claims:      Claims{"iss": "other"},
+			cfg:         Config{Issuer: "auth"},
+			expectedErr: ErrInvalidIssuer,
+		},
+		{
+			name:        "invalid audience",
+			claims:      Claims{"aud": "orders"},
+			cfg:         Config{Audience: []string{"users"}},
+			expectedErr: ErrInvalidAudience,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.cfg.Keys = StaticKey(secret)
+			test.cfg.Now = func() time.Time { return testNow }
+			verifier := NewVerifier(test.cfg)
+
+			_, err := verifier.Verify(context.Background(), signToken(t, HS256, "", secret, test.claims))
+			if test.expectedErr != nil {
+				assert.ErrorIs(t, err, test.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestClaims_Time(t *testing.T) {
+	exp, ok := Claims{"exp": float64(1e11)}.ExpiresAt()
+	assert.True(t, ok)
+	assert.Equal(t, 5138, exp.UTC().Year())
+
+	_, ok = Claims{"exp": float64(1e12)}.ExpiresAt()
+	assert.False(t, ok)
+
+	exp, ok = Claims{"exp": 1714557600.5}.ExpiresAt()
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2024, 5, 1, 10, 0, 0, int(500*time.Millisecond), time.UTC), exp.UTC())
+
+	_, ok = Claims{"exp": "1714557600"}.ExpiresAt()
+	assert.False(t, ok)
+	_, ok = Claims{}.ExpiresAt()
+	assert.False(t, ok)
+}
+
+func TestClaims_Scopes(t *testing.T) {
+	assert.Equal(t, []string{"read", "write"}, Claims{"scope": "read write"}.Scopes())
+	assert.Equal(t, []string{"read"}, Claims{"scp": []any{"read"}}.Scopes())
+	assert.Nil(t, Claims{}.Scopes())
+}
@@ -0,0 +1,157 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/Moranilt/http-utils/response"
+	"github.com/Moranilt/http-utils/tiny_errors"
+)
+
+const (
+	ERR_CODE_Unauthorized = 996
+	ERR_CODE_Forbidden    = 995
+)
+
+const (
+	ErrMissingToken      = "missing token"
+	ErrInvalidToken      = "invalid token"
+	ErrInsufficientScope = "insufficient scope"
+)
+
+func init() {
+	tiny_errors.MustRegister(
+		tiny_errors.Definition{
+			Code:        ERR_CODE_Unauthorized,
+			Name:        "Unauthorized",
+			Message:     ErrMissingToken,
+			HTTPStatus:  http.StatusUnauthorized,
+			Severity:    tiny_errors.SeverityWarning,
+			Description: "Bearer token is missing or not valid",
+		},
+		tiny_errors.Definition{
+			Code:        ERR_CODE_Forbidden,
+			Name:        "Forbidden",
+			Message:     ErrInsufficientScope,
+			HTTPStatus:  http.StatusForbidden,
+			Severity:    tiny_errors.SeverityWarning,
+			Description: "Token does not have required scopes",
+		},
+	)
+}
+
+type contextKey string
+
+const ctxClaims contextKey = "jwt_claims"
+
+// Store claims in context
+func ContextWithClaims(ctx context.Context, claims Claims) context.Context {
+	return context.WithValue(ctx, ctxClaims, claims)
+}
+
+// Get claims from context
+func ClaimsFromContext(ctx context.Context) (Claims, bool) {
+	claims, ok := ctx.Value(ctxClaims).(Claims)
+	return claims, ok
+}
+
+// Returns error with status 401
+func Unauthorized(message string) tiny_errors.ErrorHandler {
+	return tiny_errors.New(
+		ERR_CODE_Unauthorized,
+		tiny_errors.Message(message),
+		tiny_errors.HTTPStatus(http.StatusUnauthorized),
+	)
+}
+
+// Returns error with status 403
+func Forbidden(message string) tiny_errors.ErrorHandler {
+	return tiny_errors.New(
+		ERR_CODE_Forbidden,
+		tiny_errors.Message(message),
+		tiny_errors.HTTPStatus(http.StatusForbidden),
+	)
+}
+
+// Get token from Authorization header with Bearer scheme
+func BearerToken(r *http.Request) string {
+	auth := r.Header.Get("Authorization")
+	scheme, token, found := strings.Cut(auth, " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
+
+// Middleware verifies bearer token and stores claims in request context.
+//
+// Requests without valid token get status 401 with tiny_errors.Error in response body.
+// Reason of rejection is logged and not sent to the client. If keys can't be loaded,
+// e.g. JWKS is unreachable, requests get status 503.
+//
+// Example:
+//
+//	verifier := jwt.NewVerifier(jwt.Config{
+//		Keys:     jwt.NewJWKSFromURL("https://auth.example.com/.well-known/jwks.json", time.Hour, nil),
+//		Issuer:   "https://auth.example.com",
+//		Audience: []string{"orders"},
+//	})
+//	router.Use(verifier.Middleware)
+func (v *Verifier) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := BearerToken(r)
+		if token == "" {
+			v.unauthorized(w, r, ErrMissingToken)
+			return
+		}
+
+		claims, err := v.Verify(r.Context(), token)
+		if errors.Is(err, ErrKeysUnavailable) {
+			v.cfg.Logger.Error("unable to verify token", "error", err.Error())
+			err := tiny_errors.New(tiny_errors.ERR_CODE_Unavailable)
+			response.ErrorResponse(w, err, err.GetHTTPStatus(), response.Request(r))
+			return
+		}
+		if err != nil {
+			v.cfg.Logger.Debug("token is rejected", "error", err.Error())
+			v.unauthorized(w, r, ErrInvalidToken)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(ContextWithClaims(r.Context(), claims)))
+	})
+}
+
+func (v *Verifier) unauthorized(w http.ResponseWriter, r *http.Request, message string) {
+	w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+	err := Unauthorized(message)
+	response.ErrorResponse(w, err, err.GetHTTPStatus(), response.Request(r))
+}
+
+// RequireScopes responds with status 403 if claims in context do not contain all of scopes.
+// Should be used after Verifier.Middleware.
+func RequireScopes(scopes ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims, ok := ClaimsFromContext(r.Context())
+			if !ok {
+				err := Unauthorized(ErrMissingToken)
+				response.ErrorResponse(w, err, err.GetHTTPStatus(), response.Request(r))
+				return
+			}
+
+			tokenScopes := claims.Scopes()
+			for _, scope := range scopes {
+				if !slices.Contains(tokenScopes, scope) {
+					err := Forbidden(ErrInsufficientScope)
+					response.ErrorResponse(w, err, err.GetHTTPStatus(), response.Request(r))
+					return
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package jwt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Moranilt/http-utils/logger"
+	"github.com/Moranilt/http-utils/response"
+	"github.com/Moranilt/http-utils/tiny_errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware(t *testing.T) {
+	secret := []byte("secret")
+	verifier := NewVerifier(Config{
+		Keys:   StaticKey(secret),
+		Now:    func() time.Time { return testNow },
+		Logger: logger.NewMock(),
+	})
+
+	var subject string
+	handler := verifier.Middleware(RequireScopes("orders:read")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, _ := ClaimsFromContext(r.Context())
+		subject = claims.Subject()
+	})))
+
+	tests := []struct {
+		name            string
+		authorization   string
+		expectedStatus  int
+		expectedCode    int
+		expectedMessage string
+	}{
+		{
+			name:           "valid token",
+			authorization:  "Bearer " + signToken(t, HS256, "", secret, Claims{"sub": "user-1", "scope": "orders:read"}),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:            "missing token",
+			expectedStatus:  http.StatusUnauthorized,
+			expectedCode:    ERR_CODE_Unauthorized,
+			expectedMessage: ErrMissingToken,
+		},
+		{
+			name:            "not bearer scheme",
+			authorization:   "Basic dXNlcjpwYXNz",
+			expectedStatus:  http.StatusUnauthorized,
+			expectedCode:    ERR_CODE_Unauthorized,
+			expectedMessage: ErrMissingToken,
+		},
+		{
+			name:            "expired token",
+			authorization:   "Bearer " + signToken(t, HS256, "", secret, Claims{"exp": testNow.Add(-time.Minute).Unix()}),
+			expectedStatus:  http.StatusUnauthorized,
+			expectedCode:    ERR_CODE_Unauthorized,
+			expectedMessage: ErrInvalidToken,
+		},
+		{
+			name:            "insufficient scope",
+			authorization:   "Bearer " + signToken(t, HS256, "", secret, Claims{"sub": "user-1", "scope": "orders:write"}),
+			expectedStatus:  http.StatusForbidden,
+			expectedCode:    ERR_CODE_Forbidden,
+			expectedMessage: ErrInsufficientScope,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			subject = ""
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if test.authorization != "" {
+				req.Header.Set("Authorization", test.authorization)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, test.expectedStatus, rec.Code)
+			if test.expectedStatus == http.StatusOK {
+				assert.Equal(t, "user-1", subject)
+				return
+			}
+
+			var resp response.DefaultResponse[any, *tiny_errors.Error]
+			err := json.NewDecoder(rec.Body).Decode(&resp)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedCode, resp.Error.Code)
+			assert.Equal(t, test.expectedMessage, resp.Error.Message)
+			assert.Empty(t, subject)
+		})
+	}
+}
+
+func TestMiddlewareKeysUnavailable(t *testing.T) {
+	verifier := NewVerifier(Config{
+		Keys: KeyFunc(func(ctx context.Context, kid string, alg string) (any, error) {
+			return nil, errors.New("dial tcp 10.0.0.1:443: connection refused")
+		}),
+		Logger: logger.NewMock(),
+	})
+	handler := verifier.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("handler should not be called")
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+signToken(t, HS256, "", []byte("secret"), Claims{"sub": "user-1"}))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "10.0.0.1")
+
+	var resp response.DefaultResponse[any, *tiny_errors.Error]
+	err := json.NewDecoder(rec.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, tiny_errors.ERR_CODE_Unavailable, resp.Error.Code)
+	assert.Equal(t, tiny_errors.ErrUnavailable, resp.Error.Message)
+}
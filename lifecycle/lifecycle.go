@@ -0,0 +1,299 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/Moranilt/http-utils/health"
+	"github.com/Moranilt/http-utils/logger"
+)
+
+const (
+	defaultShutdownTimeout = 30 * time.Second
+	defaultStepTimeout     = 10 * time.Second
+)
+
+// Closer releases resources of a component
+type Closer func(ctx context.Context) error
+
+// Adapter for Close methods without context, such as rabbitmq.Close or sql.DB.Close
+func CloserFunc(close func() error) Closer {
+	return func(ctx context.Context) error {
+		return close()
+	}
+}
+
+// Worker runs until ctx is canceled. Returned error stops the application
+type Worker func(ctx context.Context) error
+
+type server struct {
+	name   string
+	server *http.Server
+}
+
+type worker struct {
+	name string
+	run  Worker
+}
+
+type closer struct {
+	name    string
+	close   Closer
+	timeout time.Duration
+}
+
+type App struct {
+	logger          logger.Logger
+	shutdownTimeout time.Duration
+	stepTimeout     time.Duration
+	drainDelay      time.Duration
+	signals         []os.Signal
+	health          *health.Health
+
+	servers []server
+	workers []worker
+	closers []closer
+	mu      sync.Mutex
+}
+
+// Option is a function type that can be used to configure an App.
+type Option func(*App)
+
+// Max time of waiting for drain delay of WithHealth, draining in-flight requests and stopping workers.
+// Default is 30 seconds
+func ShutdownTimeout(timeout time.Duration) Option {
+	return func(a *App) {
+		a.shutdownTimeout = timeout
+	}
+}
+
+// Default timeout of closing one component. Default is 10 seconds
+func StepTimeout(timeout time.Duration) Option {
+	return func(a *App) {
+		a.stepTimeout = timeout
+	}
+}
+
+// Signals which start graceful shutdown. Default is SIGINT and SIGTERM.
+// The second signal during shutdown is handled by default, e.g. terminates the process
+func Signals(signals ...os.Signal) Option {
+	return func(a *App) {
+		a.signals = signals
+	}
+}
+
+// Make readiness probe of h fail when shutdown is started and wait for delay
+// before stopping servers, so load balancers stop sending new requests.
+// Delay is a part of ShutdownTimeout.
+func WithHealth(h *health.Health, delay time.Duration) Option {
+	return func(a *App) {
+		a.health = h
+		a.drainDelay = delay
+	}
+}
+
+// Create new App
+//
+// Example:
+//
+//	app := lifecycle.New(log)
+//	app.AddCloser("postgres", lifecycle.CloserFunc(db.Close))
+//	app.AddCloser("rabbitmq", lifecycle.CloserFunc(rabbitmq.Close))
+//	app.AddServer("http", &http.Server{Addr: ":8080", Handler: router})
+//	app.AddWorker("consumer", func(ctx context.Context) error {
+//		rabbitClient.ReadMsgs(ctx, 10, time.Second, callback)
+//		return nil
+//	})
+//	if err := app.Run(context.Background()); err != nil {
+//		log.Fatal(err.Error())
+//	}
+func New(log logger.Logger, options ...Option) *App {
+	a := &App{
+		logger:          log,
+		shutdownTimeout: defaultShutdownTimeout,
+		stepTimeout:     defaultStepTimeout,
+		signals:         []os.Signal{syscall.SIGINT, syscall.SIGTERM},
+	}
+
+	for _, opt := range options {
+		opt(a)
+	}
+
+	return a
+}
+
+// Add HTTP server which is started by Run and gracefully stopped on shutdown
+func (a *App) AddServer(name string, srv *http.Server) {
+	a.mu.Lock()
+	a.servers = append(a.servers, server{name: name, server: srv})
+	a.mu.Unlock()
+}
+
+// Add background worker. Context of worker is canceled on shutdown
+func (a *App) AddWorker(name string, run Worker) {
+	a.mu.Lock()
+	a.workers = append(a.workers, worker{name: name, run: run})
+	a.mu.Unlock()
+}
+
+// Add component which is closed on shutdown after servers and workers are stopped.
+//
+// Components are closed in reverse order of adding, so dependencies should be added first.
+// If timeout is not set, StepTimeout of the App is used.
+func (a *App) AddCloser(name string, close Closer, timeout ...time.Duration) {
+	c := closer{name: name, close: close, timeout: a.stepTimeout}
+	if len(timeout) > 0 {
+		c.timeout = timeout[0]
+	}
+	a.mu.Lock()
+	a.closers = append(a.closers, c)
+	a.mu.Unlock()
+}
+
+// Run servers and workers and wait for a signal, cancellation of ctx or
+// error of any server or worker. Then shutdown the application.
+//
+// Returns the error which stopped the application and errors of shutdown.
+func (a *App) Run(ctx context.Context) error {
+	a.mu.Lock()
+	servers := a.servers
+	workers := a.workers
+	closers := a.closers
+	a.mu.Unlock()
+
+	ctx, stop := signal.NotifyContext(ctx, a.signals...)
+
+	errCh := make(chan error, len(servers)+len(workers))
+
+	for _, s := range servers {
+		go func(s server) {
+			a.logger.Info("starting server", "name", s.name, "addr", s.server.Addr)
+			if err := s.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				errCh <- fmt.Errorf("server %q: %w", s.name, err)
+			}
+		}(s)
+	}
+
+	workersCtx, cancelWorkers := context.WithCancel(context.Background())
+	defer cancelWorkers()
+	var workersWG sync.WaitGroup
+	for _, w := range workers {
+		workersWG.Add(1)
+		go func(w worker) {
+			defer workersWG.Done()
+			a.logger.Info("starting worker", "name", w.name)
+			if err := w.run(workersCtx); err != nil && !errors.Is(err, context.Canceled) {
+				errCh <- fmt.Errorf("worker %q: %w", w.name, err)
+			}
+		}(w)
+	}
+
+	var runErr error
+	select {
+	case <-ctx.Done():
+		a.logger.Info("shutdown started")
+	case runErr = <-errCh:
+		a.logger.Error("shutdown started by error", "error", runErr.Error())
+	}
+	// Restore default handling of signals, so the process can be killed by the second signal
+	stop()
+
+	return errors.Join(runErr, a.shutdown(servers, cancelWorkers, &workersWG, closers))
+}
+
+func (a *App) shutdown(servers []server, cancelWorkers context.CancelFunc, workersWG *sync.WaitGroup, closers []closer) error {
+	ctx, cancel := context.WithTimeout(context.Background(), a.shutdownTimeout)
+	defer cancel()
+
+	var (
+		errs []error
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+	)
+	if a.health != nil {
+		a.health.SetShuttingDown()
+		if a.drainDelay > 0 {
+			a.logger.Info("waiting before stopping servers", "delay", a.drainDelay.String())
+			timer := time.NewTimer(a.drainDelay)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				a.logger.Error("drain delay is longer than shutdown timeout")
+				errs = append(errs, fmt.Errorf("drain delay: %w", ctx.Err()))
+			}
+		}
+	}
+
+	for _, s := range servers {
+		wg.Add(1)
+		go func(s server) {
+			defer wg.Done()
+			a.logger.Info("stopping server", "name", s.name)
+			if err := s.server.Shutdown(ctx); err != nil {
+				a.logger.Error("unable to stop server", "name", s.name, "error", err.Error())
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("server %q: %w", s.name, err))
+				mu.Unlock()
+				return
+			}
+			a.logger.Info("server stopped", "name", s.name)
+		}(s)
+	}
+	wg.Wait()
+
+	cancelWorkers()
+	workersDone := make(chan struct{})
+	go func() {
+		workersWG.Wait()
+		close(workersDone)
+	}()
+	select {
+	case <-workersDone:
+		a.logger.Info("workers stopped")
+	case <-ctx.Done():
+		a.logger.Error("workers are not stopped in time")
+		errs = append(errs, fmt.Errorf("workers: %w", ctx.Err()))
+	}
+
+	for i := len(closers) - 1; i >= 0; i-- {
+		if err := a.close(closers[i]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	a.logger.Info("shutdown finished")
+	return errors.Join(errs...)
+}
+
+func (a *App) close(c closer) error {
+	a.logger.Info("closing", "name", c.name)
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- c.close(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-errCh:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	if err != nil {
+		a.logger.Error("unable to close", "name", c.name, "error", err.Error())
+		return fmt.Errorf("close %q: %w", c.name, err)
+	}
+	a.logger.Info("closed", "name", c.name)
+	return nil
+}
@@ -0,0 +1,159 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Moranilt/http-utils/health"
+	"github.com/Moranilt/http-utils/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+func freeAddr(t testing.TB) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	return l.Addr().String()
+}
+
+func waitForServer(t testing.TB, addr string) {
+	t.Helper()
+	for i := 0; i < 100; i++ {
+		conn, err := net.Dial("tcp", addr)
+		if err == nil {
+			conn.Close()
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("server %s is not started", addr)
+}
+
+func TestApp_GracefulShutdown(t *testing.T) {
+	addr := freeAddr(t)
+	requestStarted := make(chan struct{})
+	mux := http.NewServeMux()
+	mux.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		close(requestStarted)
+		time.Sleep(200 * time.Millisecond)
+		w.Write([]byte("done"))
+	})
+
+	var (
+		order []string
+		mu    sync.Mutex
+	)
+	record := func(name string) {
+		mu.Lock()
+		order = append(order, name)
+		mu.Unlock()
+	}
+
+	h := health.New()
+	app := New(logger.NewMock(), WithHealth(h, 0))
+	app.AddServer("http", &http.Server{Addr: addr, Handler: mux})
+	app.AddWorker("consumer", func(ctx context.Context) error {
+		<-ctx.Done()
+		record("worker")
+		return nil
+	})
+	app.AddCloser("database", func(ctx context.Context) error {
+		record("database")
+		return nil
+	})
+	app.AddCloser("rabbitmq", CloserFunc(func() error {
+		record("rabbitmq")
+		return nil
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	runErr := make(chan error, 1)
+	go func() {
+		runErr <- app.Run(ctx)
+	}()
+	waitForServer(t, addr)
+
+	respCh := make(chan string, 1)
+	go func() {
+		resp, err := http.Get("http://" + addr + "/slow")
+		if err != nil {
+			respCh <- err.Error()
+			return
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		respCh <- string(body)
+	}()
+
+	<-requestStarted
+	cancel()
+
+	assert.NoError(t, <-runErr)
+	assert.Equal(t, "done", <-respCh)
+	assert.Equal(t, []string{"worker", "rabbitmq", "database"}, order)
+	assert.Equal(t, health.StatusFail, h.Ready(context.Background()).Status)
+}
+
+func TestApp_WorkerError(t *testing.T) {
+	var closed bool
+	app := New(logger.NewMock())
+	app.AddWorker("consumer", func(ctx context.Context) error {
+		return errors.New("connection lost")
+	})
+	app.AddCloser("database", CloserFunc(func() error {
+		closed = true
+		return nil
+	}))
+
+	err := app.Run(context.Background())
+	assert.ErrorContains(t, err, `worker "consumer": connection lost`)
+	assert.True(t, closed)
+}
+
+func TestApp_CloserTimeout(t *testing.T) {
+	var closed bool
+	app := New(logger.NewMock(), StepTimeout(time.Second))
+	app.AddCloser("database", CloserFunc(func() error {
+		closed = true
+		return nil
+	}))
+	app.AddCloser("stuck", func(ctx context.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	}, 20*time.Millisecond)
+	app.AddCloser("broken", CloserFunc(func() error {
+		return errors.New("already closed")
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := app.Run(ctx)
+	assert.ErrorContains(t, err, `close "broken": already closed`)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.True(t, closed)
+}
+
+func TestApp_DrainDelayTimeout(t *testing.T) {
+	h := health.New()
+	app := New(logger.NewMock(), WithHealth(h, time.Minute), ShutdownTimeout(50*time.Millisecond))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	started := time.Now()
+	err := app.Run(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.ErrorContains(t, err, "drain delay")
+	assert.Less(t, time.Since(started), time.Second)
+	assert.Equal(t, health.StatusFail, h.Ready(context.Background()).Status)
+}
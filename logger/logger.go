@@ -48,6 +48,23 @@ const (
 	CtxRequestId ContextKey = "request_id"
 )
 
+// Header used to pass request id between services
+const HeaderRequestId = "X-Request-ID"
+
+// Store request id in context
+func ContextWithRequestId(ctx context.Context, requestId string) context.Context {
+	return context.WithValue(ctx, CtxRequestId, requestId)
+}
+
+// Get request id from context. Returns empty string if request id is not set
+func RequestId(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	requestId, _ := ctx.Value(CtxRequestId).(string)
+	return requestId
+}
+
 const (
 	LevelTrace  = slog.Level(-8)
 	LevelDebug  = slog.Level(-4)
@@ -213,7 +230,7 @@ func (l *SLogger) WithRequestInfo(r *http.Request) Logger {
 	)
 }
 func (l *SLogger) WithRequestId(ctx context.Context) Logger {
-	requestId := ctx.Value(CtxRequestId)
+	requestId := RequestId(ctx)
 	if requestId != "" {
 		return &SLogger{
 			root: l.root.With("request_id", requestId),
@@ -0,0 +1,362 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/Moranilt/http-utils/response"
+	"github.com/Moranilt/http-utils/tiny_errors"
+	"github.com/gorilla/mux"
+)
+
+const (
+	ERR_CODE_CORSForbidden = 997
+)
+
+const (
+	ErrCORSOriginNotAllowed  = "origin not allowed"
+	ErrCORSMethodNotAllowed  = "method not allowed"
+	ErrCORSHeadersNotAllowed = "headers not allowed"
+)
+
+func init() {
+	tiny_errors.MustRegister(tiny_errors.Definition{
+		Code:        ERR_CODE_CORSForbidden,
+		Name:        "CORSForbidden",
+		Message:     ErrCORSOriginNotAllowed,
+		HTTPStatus:  http.StatusForbidden,
+		Severity:    tiny_errors.SeverityWarning,
+		Description: "Origin, method or headers of cross-origin request are not allowed",
+	})
+}
+
+const (
+	headerOrigin           = "Origin"
+	headerVary             = "Vary"
+	headerRequestMethod    = "Access-Control-Request-Method"
+	headerRequestHeaders   = "Access-Control-Request-Headers"
+	headerAllowOrigin      = "Access-Control-Allow-Origin"
+	headerAllowMethods     = "Access-Control-Allow-Methods"
+	headerAllowHeaders     = "Access-Control-Allow-Headers"
+	headerAllowCredentials = "Access-Control-Allow-Credentials"
+	headerExposeHeaders    = "Access-Control-Expose-Headers"
+	headerMaxAge           = "Access-Control-Max-Age"
+)
+
+var defaultCORSAllowedMethods = []string{http.MethodGet, http.MethodHead, http.MethodPost}
+
+type CORSConfig struct {
+	// Allowed origins. Supported values:
+	//   - exact origin: "https://example.com"
+	//   - wildcard subdomain: "https://*.example.com"
+	//   - any origin: "*"
+	AllowedOrigins []string
+
+	// Custom check of origin. Called if origin does not match AllowedOrigins
+	AllowOriginFunc func(origin string) bool
+
+	// Allowed methods. If empty, methods are taken from routes of Router matching
+	// the request path. Routes are collected on the first preflight request, so they should
+	// be registered before the server starts. If Router is not set, GET, HEAD and POST are allowed
+	AllowedMethods []string
+
+	// Allowed request headers. "*" allows any header
+	AllowedHeaders []string
+
+	// Headers which are accessible by client
+	ExposedHeaders []string
+
+	// Allow cookies and authorization headers. Can't be used with any origin "*"
+	AllowCredentials bool
+
+	// How long (in seconds) the results of a preflight request can be cached. Zero - header is not sent
+	MaxAge int
+
+	// Router is used to detect allowed methods of the route
+	Router *mux.Router
+}
+
+type cors struct {
+	cfg            CORSConfig
+	allowAll       bool
+	origins        map[string]struct{}
+	wildcards      []corsWildcard
+	allowedHeaders map[string]struct{}
+	allowAnyHeader bool
+
+	routesOnce sync.Once
+	routes     []corsRoute
+}
+
+// Origin with wildcard subdomain, e.g. "https://*.example.com" has prefix "https://"
+// and suffix ".example.com"
+type corsWildcard struct {
+	prefix string
+	suffix string
+}
+
+// Route of Router with its methods
+type corsRoute struct {
+	route   *mux.Route
+	methods []string
+}
+
+// CORS middleware sets CORS headers for requests with allowed origin and responds
+// to preflight requests.
+//
+// Rejected preflight requests get status 403 with tiny_errors.Error in response body.
+//
+// gorilla/mux does not run middlewares for requests which are not matched with any route,
+// so preflight OPTIONS requests reach the middleware only if the router is wrapped:
+//
+//	router := mux.NewRouter()
+//	cors := middleware.CORS(middleware.CORSConfig{
+//		AllowedOrigins: []string{"https://*.example.com"},
+//		AllowedHeaders: []string{"Content-Type", "Authorization"},
+//		Router:         router,
+//	})
+//	http.ListenAndServe(":8080", cors(router))
+//
+// Panics if AllowCredentials is used with any origin "*", because it allows any site
+// to make requests with credentials of the user. Panics if origin contains "*" in other
+// place than subdomain, e.g. "https://example.*".
+func CORS(cfg CORSConfig) func(http.Handler) http.Handler {
+	c := &cors{
+		cfg:            cfg,
+		origins:        make(map[string]struct{}),
+		allowedHeaders: make(map[string]struct{}),
+	}
+
+	for _, origin := range cfg.AllowedOrigins {
+		origin = strings.ToLower(origin)
+		if origin == "*" {
+			c.allowAll = true
+			continue
+		}
+		if strings.Contains(origin, "*") {
+			wildcard, ok := parseCORSWildcard(origin)
+			if !ok {
+				panic(fmt.Sprintf(`middleware: CORS origin %q is not valid, wildcard is allowed only as "scheme://*.domain"`, origin))
+			}
+			c.wildcards = append(c.wildcards, wildcard)
+			continue
+		}
+		c.origins[origin] = struct{}{}
+	}
+	if c.allowAll && cfg.AllowCredentials {
+		panic(`middleware: CORS with AllowedOrigins "*" can't be used with AllowCredentials`)
+	}
+
+	for _, header := range cfg.AllowedHeaders {
+		if header == "*" {
+			c.allowAnyHeader = true
+			continue
+		}
+		c.allowedHeaders[http.CanonicalHeaderKey(header)] = struct{}{}
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Response depends on Origin even without it, so caches do not send
+			// response without CORS headers to cross-origin clients
+			w.Header().Add(headerVary, headerOrigin)
+			origin := r.Header.Get(headerOrigin)
+			if origin == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if r.Method == http.MethodOptions && r.Header.Get(headerRequestMethod) != "" {
+				c.preflight(w, r, origin)
+				return
+			}
+
+			if c.originAllowed(origin) {
+				c.setOrigin(w, origin)
+				if len(cfg.ExposedHeaders) > 0 {
+					w.Header().Set(headerExposeHeaders, strings.Join(cfg.ExposedHeaders, ", "))
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func (c *cors) preflight(w http.ResponseWriter, r *http.Request, origin string) {
+	w.Header().Add(headerVary, headerRequestMethod)
+	w.Header().Add(headerVary, headerRequestHeaders)
+
+	if !c.originAllowed(origin) {
+		c.reject(w, r, ErrCORSOriginNotAllowed)
+		return
+	}
+
+	method := strings.ToUpper(r.Header.Get(headerRequestMethod))
+	methods := c.allowedMethods(r)
+	if !slices.Contains(methods, method) {
+		c.reject(w, r, ErrCORSMethodNotAllowed)
+		return
+	}
+
+	requestHeaders := parseHeaderList(r.Header.Get(headerRequestHeaders))
+	if !c.headersAllowed(requestHeaders) {
+		c.reject(w, r, ErrCORSHeadersNotAllowed)
+		return
+	}
+
+	c.setOrigin(w, origin)
+	w.Header().Set(headerAllowMethods, strings.Join(methods, ", "))
+	if len(requestHeaders) > 0 {
+		w.Header().Set(headerAllowHeaders, strings.Join(requestHeaders, ", "))
+	}
+	if c.cfg.MaxAge > 0 {
+		w.Header().Set(headerMaxAge, strconv.Itoa(c.cfg.MaxAge))
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (c *cors) reject(w http.ResponseWriter, r *http.Request, message string) {
+	err := tiny_errors.New(
+		ERR_CODE_CORSForbidden,
+		tiny_errors.Message(message),
+		tiny_errors.HTTPStatus(http.StatusForbidden),
+	)
+	response.ErrorResponse(w, err, err.GetHTTPStatus(), response.Request(r))
+}
+
+func (c *cors) setOrigin(w http.ResponseWriter, origin string) {
+	if c.allowAll {
+		w.Header().Set(headerAllowOrigin, "*")
+	} else {
+		w.Header().Set(headerAllowOrigin, origin)
+	}
+	if c.cfg.AllowCredentials {
+		w.Header().Set(headerAllowCredentials, "true")
+	}
+}
+
+func (c *cors) originAllowed(origin string) bool {
+	if c.allowAll {
+		return true
+	}
+
+	lowerOrigin := strings.ToLower(origin)
+	if _, ok := c.origins[lowerOrigin]; ok {
+		return true
+	}
+
+	for _, w := range c.wildcards {
+		if w.match(lowerOrigin) {
+			return true
+		}
+	}
+
+	if c.cfg.AllowOriginFunc != nil {
+		return c.cfg.AllowOriginFunc(origin)
+	}
+	return false
+}
+
+func (c *cors) allowedMethods(r *http.Request) []string {
+	if len(c.cfg.AllowedMethods) > 0 {
+		methods := make([]string, len(c.cfg.AllowedMethods))
+		for i, method := range c.cfg.AllowedMethods {
+			methods[i] = strings.ToUpper(method)
+		}
+		return methods
+	}
+
+	if c.cfg.Router == nil {
+		return defaultCORSAllowedMethods
+	}
+
+	c.routesOnce.Do(c.collectRoutes)
+
+	var methods []string
+	req := r.Clone(r.Context())
+	for _, route := range c.routes {
+		for _, method := range route.methods {
+			if slices.Contains(methods, method) {
+				continue
+			}
+			req.Method = method
+			var match mux.RouteMatch
+			if route.route.Match(req, &match) {
+				methods = append(methods, method)
+			}
+		}
+	}
+	return methods
+}
+
+// Collect routes of Router with methods
+func (c *cors) collectRoutes() {
+	c.cfg.Router.Walk(func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+		methods, err := route.GetMethods()
+		if err != nil {
+			return nil
+		}
+		c.routes = append(c.routes, corsRoute{route: route, methods: methods})
+		return nil
+	})
+}
+
+// Parse origin in form "scheme://*.domain"
+func parseCORSWildcard(origin string) (corsWildcard, bool) {
+	scheme, host, ok := strings.Cut(origin, "://")
+	if !ok || scheme == "" || !strings.HasPrefix(host, "*.") {
+		return corsWildcard{}, false
+	}
+	suffix := host[1:]
+	if len(suffix) < 2 || strings.ContainsAny(suffix, "*/") {
+		return corsWildcard{}, false
+	}
+	return corsWildcard{prefix: scheme + "://", suffix: suffix}, true
+}
+
+// Origin matches if it has the scheme, the domain and non-empty subdomain of
+// letters, digits, "-" and "."
+func (w corsWildcard) match(origin string) bool {
+	if len(origin) <= len(w.prefix)+len(w.suffix) ||
+		!strings.HasPrefix(origin, w.prefix) ||
+		!strings.HasSuffix(origin, w.suffix) {
+		return false
+	}
+	subdomain := origin[len(w.prefix) : len(origin)-len(w.suffix)]
+	for _, r := range subdomain {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' && r != '.' {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *cors) headersAllowed(headers []string) bool {
+	if c.allowAnyHeader {
+		return true
+	}
+	for _, header := range headers {
+		if _, ok := c.allowedHeaders[http.CanonicalHeaderKey(header)]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func parseHeaderList(value string) []string {
+	if value == "" {
+		return nil
+	}
+	var headers []string
+	for _, header := range strings.Split(value, ",") {
+		header = strings.TrimSpace(header)
+		if header != "" {
+			headers = append(headers, header)
+		}
+	}
+	return headers
+}
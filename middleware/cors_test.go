@@ -0,0 +1,236 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Moranilt/http-utils/response"
+	"github.com/Moranilt/http-utils/tiny_errors"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func makeCORSRequest(method, origin string, headers map[string]string) *http.Request {
+	req := httptest.NewRequest(method, "/users/1", nil)
+	if origin != "" {
+		req.Header.Set(headerOrigin, origin)
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	return req
+}
+
+func TestCORS(t *testing.T) {
+	router := mux.NewRouter()
+	router.HandleFunc("/users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}).Methods(http.MethodGet, http.MethodPut)
+	router.HandleFunc("/orders", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}).Methods(http.MethodDelete)
+
+	handler := CORS(CORSConfig{
+		AllowedOrigins: []string{"https://example.com", "https://*.example.org"},
+		AllowOriginFunc: func(origin string) bool {
+			return strings.HasSuffix(origin, ".local")
+		},
+		AllowedHeaders: []string{"content-type", "Authorization"},
+		ExposedHeaders: []string{"X-Request-ID"},
+		MaxAge:         600,
+		Router:         router,
+	})(router)
+
+	tests := []struct {
+		name            string
+		request         *http.Request
+		expectedStatus  int
+		expectedOrigin  string
+		expectedMethods string
+		expectedError   string
+	}{
+		{
+			name:           "request without origin",
+			request:        makeCORSRequest(http.MethodGet, "", nil),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "exact origin",
+			request:        makeCORSRequest(http.MethodGet, "https://example.com", nil),
+			expectedStatus: http.StatusOK,
+			expectedOrigin: "https://example.com",
+		},
+		{
+			name:           "wildcard subdomain",
+			request:        makeCORSRequest(http.MethodGet, "https://api.example.org", nil),
+			expectedStatus: http.StatusOK,
+			expectedOrigin: "https://api.example.org",
+		},
+		{
+			name:           "wildcard without subdomain",
+			request:        makeCORSRequest(http.MethodGet, "https://.example.org", nil),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "wildcard with other domain",
+			request:        makeCORSRequest(http.MethodGet, "https://evil.com/.example.org", nil),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "origin func",
+			request:        makeCORSRequest(http.MethodGet, "http://app.local", nil),
+			expectedStatus: http.StatusOK,
+			expectedOrigin: "http://app.local",
+		},
+		{
+			name:           "not allowed origin",
+			request:        makeCORSRequest(http.MethodGet, "https://evil.com", nil),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "preflight",
+			request: makeCORSRequest(http.MethodOptions, "https://example.com", map[string]string{
+				headerRequestMethod:  http.MethodPut,
+				headerRequestHeaders: "Content-Type, authorization",
+			}),
+			expectedStatus:  http.StatusNoContent,
+			expectedOrigin:  "https://example.com",
+			expectedMethods: "GET, PUT",
+		},
+		{
+			name: "preflight with not allowed origin",
+			request: makeCORSRequest(http.MethodOptions, "https://evil.com", map[string]string{
+				headerRequestMethod: http.MethodPut,
+			}),
+			expectedStatus: http.StatusForbidden,
+			expectedError:  ErrCORSOriginNotAllowed,
+		},
+		{
+			name: "preflight with method of another route",
+			request: makeCORSRequest(http.MethodOptions, "https://example.com", map[string]string{
+				headerRequestMethod: http.MethodDelete,
+			}),
+			expectedStatus: http.StatusForbidden,
+			expectedError:  ErrCORSMethodNotAllowed,
+		},
+		{
+			name: "preflight with not allowed header",
+			request: makeCORSRequest(http.MethodOptions, "https://example.com", map[string]string{
+				headerRequestMethod:  http.MethodGet,
+				headerRequestHeaders: "X-Custom",
+			}),
+			expectedStatus: http.StatusForbidden,
+			expectedError:  ErrCORSHeadersNotAllowed,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, test.request)
+
+			assert.Equal(t, test.expectedStatus, rec.Code)
+			assert.Equal(t, test.expectedOrigin, rec.Header().Get(headerAllowOrigin))
+			assert.Equal(t, test.expectedMethods, rec.Header().Get(headerAllowMethods))
+
+			if test.expectedError != "" {
+				var resp response.DefaultResponse[any, *tiny_errors.Error]
+				err := json.NewDecoder(rec.Body).Decode(&resp)
+				assert.NoError(t, err)
+				assert.Equal(t, ERR_CODE_CORSForbidden, resp.Error.Code)
+				assert.Equal(t, test.expectedError, resp.Error.Message)
+			}
+		})
+	}
+}
+
+func TestCORS_Preflight(t *testing.T) {
+	handler := CORS(CORSConfig{
+		AllowedOrigins:   []string{"https://example.com"},
+		AllowedHeaders:   []string{"*"},
+		AllowCredentials: true,
+		MaxAge:           600,
+	})(http.NotFoundHandler())
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, makeCORSRequest(http.MethodOptions, "https://example.com", map[string]string{
+		headerRequestMethod:  http.MethodPost,
+		headerRequestHeaders: "X-Custom",
+	}))
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, "https://example.com", rec.Header().Get(headerAllowOrigin))
+	assert.Equal(t, "true", rec.Header().Get(headerAllowCredentials))
+	assert.Equal(t, "GET, HEAD, POST", rec.Header().Get(headerAllowMethods))
+	assert.Equal(t, "X-Custom", rec.Header().Get(headerAllowHeaders))
+	assert.Equal(t, "600", rec.Header().Get(headerMaxAge))
+	assert.Equal(t, []string{headerOrigin, headerRequestMethod, headerRequestHeaders}, rec.Header().Values(headerVary))
+}
+
+func TestCORS_AnyOrigin(t *testing.T) {
+	handler := CORS(CORSConfig{
+		AllowedOrigins: []string{"*"},
+		ExposedHeaders: []string{"X-Request-ID"},
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, makeCORSRequest(http.MethodGet, "https://example.com", nil))
+
+	assert.Equal(t, "*", rec.Header().Get(headerAllowOrigin))
+	assert.Equal(t, "X-Request-ID", rec.Header().Get(headerExposeHeaders))
+	assert.Empty(t, rec.Header().Get(headerAllowCredentials))
+}
+
+func TestCORS_AnyOriginWithCredentials(t *testing.T) {
+	assert.Panics(t, func() {
+		CORS(CORSConfig{
+			AllowedOrigins:   []string{"https://example.com", "*"},
+			AllowCredentials: true,
+		})
+	})
+	assert.NotPanics(t, func() {
+		CORS(CORSConfig{
+			AllowedOrigins:   []string{"https://*.example.com"},
+			AllowCredentials: true,
+		})
+	})
+}
+
+func TestCORS_WildcardOrigins(t *testing.T) {
+	for _, origin := range []string{"https://example.*", "https://*example.com", "*.example.com", "https://api.*.example.com", "https://*.", "https://*.example.com/*"} {
+		assert.Panics(t, func() {
+			CORS(CORSConfig{AllowedOrigins: []string{origin}})
+		}, origin)
+	}
+
+	handler := CORS(CORSConfig{
+		AllowedOrigins: []string{"https://*.example.com"},
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	for origin, allowed := range map[string]bool{
+		"https://api.example.com":       true,
+		"https://a.b.example.com":       true,
+		"https://evilexample.com":       false,
+		"http://api.example.com":        false,
+		"https://example.com":           false,
+		"https://evil.com#.example.com": false,
+	} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, makeCORSRequest(http.MethodGet, origin, nil))
+		assert.Equal(t, allowed, rec.Header().Get(headerAllowOrigin) != "", origin)
+	}
+}
+
+func TestCORS_VaryWithoutOrigin(t *testing.T) {
+	handler := CORS(CORSConfig{
+		AllowedOrigins: []string{"https://example.com"},
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, makeCORSRequest(http.MethodGet, "", nil))
+	assert.Equal(t, []string{headerOrigin}, rec.Header().Values(headerVary))
+	assert.Empty(t, rec.Header().Get(headerAllowOrigin))
+}
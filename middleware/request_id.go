@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/Moranilt/http-utils/logger"
+	"github.com/google/uuid"
+)
+
+// Max length of inbound request id
+const MaxRequestIdLength = 128
+
+// RequestID middleware takes request id from X-Request-ID header or generates
+// a new UUID if header is empty or not valid.
+//
+// Request id is stored in request context with key logger.CtxRequestId
+// and is sent back to the client in X-Request-ID header.
+//
+// Example:
+//
+//	router := mux.NewRouter()
+//	router.Use(middleware.RequestID)
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestId := r.Header.Get(logger.HeaderRequestId)
+		if !ValidRequestId(requestId) {
+			requestId = uuid.NewString()
+		}
+
+		w.Header().Set(logger.HeaderRequestId, requestId)
+		ctx := logger.ContextWithRequestId(r.Context(), requestId)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// ValidRequestId checks if inbound request id is not empty, is not longer than
+// MaxRequestIdLength and contains only letters, digits and symbols "-", "_", ".", ":"
+func ValidRequestId(id string) bool {
+	if id == "" || len(id) > MaxRequestIdLength {
+		return false
+	}
+
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z':
+		case c >= 'A' && c <= 'Z':
+		case c >= '0' && c <= '9':
+		case c == '-' || c == '_' || c == '.' || c == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
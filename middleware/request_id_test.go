@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Moranilt/http-utils/logger"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestID(t *testing.T) {
+	var ctxRequestId string
+	handler := RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctxRequestId = logger.RequestId(r.Context())
+	}))
+
+	t.Run("inbound request id", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(logger.HeaderRequestId, "abc-123")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, "abc-123", ctxRequestId)
+		assert.Equal(t, "abc-123", rec.Header().Get(logger.HeaderRequestId))
+	})
+
+	t.Run("generated request id", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		_, err := uuid.Parse(ctxRequestId)
+		assert.NoError(t, err)
+		assert.Equal(t, ctxRequestId, rec.Header().Get(logger.HeaderRequestId))
+	})
+
+	t.Run("not valid inbound request id", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(logger.HeaderRequestId, "<script>")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		assert.NotEqual(t, "<script>", ctxRequestId)
+		_, err := uuid.Parse(ctxRequestId)
+		assert.NoError(t, err)
+	})
+}
+
+func TestValidRequestId(t *testing.T) {
+	tests := []struct {
+		name     string
+		id       string
+		expected bool
+	}{
+		{name: "uuid", id: uuid.NewString(), expected: true},
+		{name: "symbols", id: "svc.name:1_a-b", expected: true},
+		{name: "empty", id: "", expected: false},
+		{name: "spaces", id: "a b", expected: false},
+		{name: "newline", id: "a\nb", expected: false},
+		{name: "too long", id: strings.Repeat("a", MaxRequestIdLength+1), expected: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, ValidRequestId(test.id))
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package modifiers
+
+import (
+	"fmt"
+	"html"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+func init() {
+	RegisterStringModifier("trim", strings.TrimSpace)
+	RegisterStringModifier("lower", strings.ToLower)
+	RegisterStringModifier("upper", strings.ToUpper)
+	RegisterStringModifier("collapse_spaces", CollapseSpaces)
+	RegisterStringModifier("strip_html", StripHTML)
+	RegisterStringModifier("unicode_nfc", norm.NFC.String)
+	RegisterStringModifier("unicode_nfkc", norm.NFKC.String)
+	RegisterModifier("default", Default)
+}
+
+var spaces = regexp.MustCompile(`[\s\p{Zs}]+`)
+
+// CollapseSpaces replaces every sequence of whitespace characters with a single space
+func CollapseSpaces(value string) string {
+	return spaces.ReplaceAllString(value, " ")
+}
+
+var htmlTags = regexp.MustCompile(`(?is)<script\b.*?</script\s*>|<style\b.*?</style\s*>|<!--.*?-->|<[a-zA-Z/!][^>]*>`)
+
+// StripHTML unescapes entities and removes HTML tags, comments and contents of script and style elements,
+// e.g. "Tom & Jerry" for "<b>Tom &amp; Jerry</b>". It repeats until value stops changing, so tags encoded
+// as entities, e.g. "&lt;script&gt;", are removed too and never appear in result. Only "<" followed by
+// a letter, "/" or "!" starts a tag, so text like "1 < 2 and 3 > 2" is kept.
+func StripHTML(value string) string {
+	for strings.ContainsAny(value, "<&") {
+		stripped := htmlTags.ReplaceAllString(html.UnescapeString(value), "")
+		if stripped == value {
+			break
+		}
+		value = stripped
+	}
+	return value
+}
+
+var durationType = reflect.TypeFor[time.Duration]()
+
+// Default sets value of param if value is zero. Nil pointers are set to pointer to value of param.
+// It can be applied to strings, booleans, numbers, time.Duration and pointers to them.
+// Params of strings are joined with spaces, so "default=hello world" sets "hello world".
+func Default(t reflect.Type, params []string) (Modifier, error) {
+	if len(params) == 0 {
+		return nil, fmt.Errorf("%w: default value is empty", ErrNotValidParam)
+	}
+	valueType := t
+	if t.Kind() == reflect.Pointer {
+		valueType = t.Elem()
+	}
+	defaultValue, err := parseValue(valueType, strings.Join(params, " "))
+	if err != nil {
+		return nil, err
+	}
+
+	return func(value reflect.Value) {
+		if !value.IsZero() {
+			return
+		}
+		if value.Kind() == reflect.Pointer {
+			value.Set(reflect.New(valueType))
+			value = value.Elem()
+		}
+		value.Set(defaultValue)
+	}, nil
+}
+
+// Parse text as value of type t
+func parseValue(t reflect.Type, text string) (reflect.Value, error) {
+	value := reflect.New(t).Elem()
+	var err error
+	switch {
+	case t == durationType:
+		var duration time.Duration
+		duration, err = time.ParseDuration(text)
+		value.SetInt(int64(duration))
+	case t.Kind() == reflect.String:
+		value.SetString(text)
+	case t.Kind() == reflect.Bool:
+		var b bool
+		b, err = strconv.ParseBool(text)
+		value.SetBool(b)
+	case value.CanInt():
+		var i int64
+		i, err = strconv.ParseInt(text, 10, t.Bits())
+		value.SetInt(i)
+	case value.CanUint():
+		var u uint64
+		u, err = strconv.ParseUint(text, 10, t.Bits())
+		value.SetUint(u)
+	case value.CanFloat():
+		var f float64
+		f, err = strconv.ParseFloat(text, t.Bits())
+		value.SetFloat(f)
+	default:
+		return value, fmt.Errorf("%w: default value of %s is not supported", ErrNotValidModifier, t)
+	}
+	if err != nil {
+		return value, fmt.Errorf("%w: %q is not %s", ErrNotValidParam, text, t)
+	}
+	return value, nil
+}
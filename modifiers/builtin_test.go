@@ -0,0 +1,81 @@
+package modifiers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollapseSpaces(t *testing.T) {
+	assert.Equal(t, " John Doe ", CollapseSpaces(" John  \t Doe\n"))
+	assert.Equal(t, "John", CollapseSpaces("John"))
+}
+
+func TestStripHTML(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected string
+	}{
+		{"plain text", "plain text"},
+		{"<b>Tom</b> &amp; <i>Jerry</i>", "Tom & Jerry"},
+		{"Hi<script>alert('x')</script>!", "Hi!"},
+		{"<style>p{color:red}</style><p>text<!-- comment --></p>", "text"},
+		{`<a href="https://example.com">link</a><br/>`, "link"},
+		{"1 &lt; 2", "1 < 2"},
+		{"1 < 2 and 3 > 2", "1 < 2 and 3 > 2"},
+		{"a <= b, c >= d", "a <= b, c >= d"},
+		{"I <3 you > all", "I <3 you > all"},
+		{"&lt;script&gt;alert(1)&lt;/script&gt;", ""},
+		{"&amp;lt;b&amp;gt;bold&amp;lt;/b&amp;gt;", "bold"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.value, func(t *testing.T) {
+			assert.Equal(t, test.expected, StripHTML(test.value))
+		})
+	}
+}
+
+func TestUnicodeNormalization(t *testing.T) {
+	value := "Cafe\u0301"
+	assert.NoError(t, Var(&value, "unicode_nfc"))
+	assert.Equal(t, "Caf\u00e9", value)
+
+	value = "ﬁle①"
+	assert.NoError(t, Var(&value, "unicode_nfkc"))
+	assert.Equal(t, "file1", value)
+}
+
+func TestDefault(t *testing.T) {
+	var (
+		name     string
+		enabled  bool
+		limit    uint8
+		ratio    float64
+		timeout  time.Duration
+		page     *int
+		filled   = 3
+		existing = &filled
+	)
+
+	assert.NoError(t, Var(&name, "default=John Doe"))
+	assert.NoError(t, Var(&enabled, "default=true"))
+	assert.NoError(t, Var(&limit, "default=200"))
+	assert.NoError(t, Var(&ratio, "default=0.5"))
+	assert.NoError(t, Var(&timeout, "default=1m30s"))
+	assert.NoError(t, Var(&page, "default=1"))
+	assert.NoError(t, Var(&existing, "default=1"))
+
+	assert.Equal(t, "John Doe", name)
+	assert.True(t, enabled)
+	assert.Equal(t, uint8(200), limit)
+	assert.Equal(t, 0.5, ratio)
+	assert.Equal(t, 90*time.Second, timeout)
+	if assert.NotNil(t, page) {
+		assert.Equal(t, 1, *page)
+	}
+	assert.Equal(t, 3, *existing)
+
+	assert.ErrorIs(t, Var(&limit, "default=300"), ErrNotValidParam)
+}
@@ -0,0 +1,171 @@
+package modifiers
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	ErrUnknownModifier  = errors.New("unknown modifier")
+	ErrNotValidModifier = errors.New("not valid modifier")
+	ErrNotValidParam    = errors.New("not valid param of modifier")
+)
+
+// Modifier changes value in place. Value is settable and has type which modifier was created for
+type Modifier func(value reflect.Value)
+
+// ModifierFactory creates modifier for values of type t with params of tag, e.g. ["10"] for "default=10".
+// Returns error wrapping ErrNotValidModifier if modifier can not be applied to type
+type ModifierFactory func(t reflect.Type, params []string) (Modifier, error)
+
+var (
+	modifierStorage atomic.Value
+	modifierMu      sync.Mutex
+)
+
+func loadModifiers() map[string]ModifierFactory {
+	modifiers, _ := modifierStorage.Load().(map[string]ModifierFactory)
+	return modifiers
+}
+
+// Register modifier which can be used in tags. Modifier registered before with the same name is replaced.
+//
+// Example:
+//
+//	modifiers.RegisterModifier("abs", func(t reflect.Type, params []string) (modifiers.Modifier, error) {
+//		if t.Kind() != reflect.Int {
+//			return nil, fmt.Errorf("%w: %s is not int", modifiers.ErrNotValidModifier, t)
+//		}
+//		return func(value reflect.Value) {
+//			if value.Int() < 0 {
+//				value.SetInt(-value.Int())
+//			}
+//		}, nil
+//	})
+func RegisterModifier(name string, factory ModifierFactory) {
+	modifierMu.Lock()
+	defer modifierMu.Unlock()
+
+	modifiers := maps.Clone(loadModifiers())
+	if modifiers == nil {
+		modifiers = make(map[string]ModifierFactory)
+	}
+	modifiers[name] = factory
+	modifierStorage.Store(modifiers)
+	clearTagCache()
+}
+
+// Register modifier without params which changes strings. It can be applied to strings,
+// pointers to strings and slices of them, see Strings
+//
+// Example:
+//
+//	modifiers.RegisterStringModifier("slug", func(value string) string {
+//		return slugify(value)
+//	})
+func RegisterStringModifier(name string, modify func(value string) string) {
+	RegisterModifier(name, func(t reflect.Type, params []string) (Modifier, error) {
+		if len(params) > 0 {
+			return nil, fmt.Errorf("%w: modifier has no params", ErrNotValidParam)
+		}
+		return Strings(t, modify)
+	})
+}
+
+// Create modifier for type t which applies modify to strings. Type should be a string, pointer to string
+// or slice or array of them. Nil pointers are not changed.
+func Strings(t reflect.Type, modify func(value string) string) (Modifier, error) {
+	if !containsStrings(t) {
+		return nil, fmt.Errorf("%w: %s does not contain strings", ErrNotValidModifier, t)
+	}
+	var apply Modifier
+	apply = func(value reflect.Value) {
+		switch value.Kind() {
+		case reflect.String:
+			value.SetString(modify(value.String()))
+		case reflect.Pointer:
+			if !value.IsNil() {
+				apply(value.Elem())
+			}
+		case reflect.Slice, reflect.Array:
+			for i := 0; i < value.Len(); i++ {
+				apply(value.Index(i))
+			}
+		}
+	}
+	return apply, nil
+}
+
+func containsStrings(t reflect.Type) bool {
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.String
+}
+
+// Create registered modifier by name with params for values of type t
+func Named(t reflect.Type, name string, params ...string) (Modifier, error) {
+	factory, ok := loadModifiers()[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownModifier, name)
+	}
+	modifier, err := factory(t, params)
+	if err != nil {
+		return nil, fmt.Errorf("modifier %q: %w", name, err)
+	}
+	return modifier, nil
+}
+
+// Parse modifiers of tag for values of type t. Modifiers are separated by "," and applied in order.
+// Params follow the name of modifier after "=" and are separated by spaces.
+//
+// Example:
+//
+//	modifier, err := modifiers.ParseTag(reflect.TypeFor[string](), "trim,lower")
+func ParseTag(t reflect.Type, tag string) (Modifier, error) {
+	var chain []Modifier
+	for _, part := range strings.Split(tag, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			return nil, fmt.Errorf("%w: empty modifier in %q", ErrNotValidModifier, tag)
+		}
+		name, params, _ := strings.Cut(part, "=")
+		modifier, err := Named(t, strings.TrimSpace(name), strings.Fields(params)...)
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, modifier)
+	}
+	if len(chain) == 1 {
+		return chain[0], nil
+	}
+	return func(value reflect.Value) {
+		for _, modifier := range chain {
+			modifier(value)
+		}
+	}, nil
+}
+
+// Apply modifiers of tag to value by pointer.
+//
+// Example:
+//
+//	email := " Foo@Example.com "
+//	err := modifiers.Var(&email, "trim,lower") // "foo@example.com"
+func Var(pointer any, tag string) error {
+	value := reflect.ValueOf(pointer)
+	if value.Kind() != reflect.Pointer || value.IsNil() {
+		return fmt.Errorf("%w: %T is not a pointer", ErrNotValidModifier, pointer)
+	}
+	modifier, err := ParseTag(value.Type().Elem(), tag)
+	if err != nil {
+		return err
+	}
+	modifier(value.Elem())
+	return nil
+}
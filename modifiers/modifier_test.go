@@ -0,0 +1,94 @@
+package modifiers
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTag(t *testing.T) {
+	tests := []struct {
+		tag      string
+		value    any
+		expected any
+	}{
+		{"trim,lower", " Foo@Example.com ", "foo@example.com"},
+		{" trim , upper ", " de ", "DE"},
+		{"collapse_spaces,trim", "  John \t\n Doe ", "John Doe"},
+		{"trim,lower", []string{" A ", "b "}, []string{"a", "b"}},
+		{"default=10", 0, 10},
+		{"default=10", 5, 5},
+	}
+
+	for _, test := range tests {
+		t.Run(test.tag, func(t *testing.T) {
+			value := reflect.New(reflect.TypeOf(test.value))
+			value.Elem().Set(reflect.ValueOf(test.value))
+			assert.NoError(t, Var(value.Interface(), test.tag))
+			assert.Equal(t, test.expected, value.Elem().Interface())
+		})
+	}
+}
+
+func TestParseTagErrors(t *testing.T) {
+	tests := []struct {
+		tag   string
+		value any
+		err   error
+	}{
+		{"trim,unknown", "", ErrUnknownModifier},
+		{"trim,", "", ErrNotValidModifier},
+		{"trim", 10, ErrNotValidModifier},
+		{"trim=1", "", ErrNotValidParam},
+		{"default", 0, ErrNotValidParam},
+		{"default=abc", 0, ErrNotValidParam},
+		{"default=1", []int{}, ErrNotValidModifier},
+	}
+
+	for _, test := range tests {
+		t.Run(test.tag, func(t *testing.T) {
+			_, err := ParseTag(reflect.TypeOf(test.value), test.tag)
+			assert.True(t, errors.Is(err, test.err), err)
+		})
+	}
+
+	var value string
+	assert.True(t, errors.Is(Var(value, "trim"), ErrNotValidModifier))
+}
+
+func TestRegisterStringModifier(t *testing.T) {
+	RegisterStringModifier("test_reverse", func(value string) string {
+		runes := []rune(value)
+		for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+			runes[i], runes[j] = runes[j], runes[i]
+		}
+		return string(runes)
+	})
+
+	value := "abc"
+	pointer := &value
+	assert.NoError(t, Var(&pointer, "test_reverse,upper"))
+	assert.Equal(t, "CBA", value)
+
+	var nilPointer *string
+	assert.NoError(t, Var(&nilPointer, "test_reverse"))
+	assert.Nil(t, nilPointer)
+}
+
+func TestRegisterModifier(t *testing.T) {
+	RegisterModifier("test_repeat", func(typ reflect.Type, params []string) (Modifier, error) {
+		if typ.Kind() != reflect.String {
+			return nil, ErrNotValidModifier
+		}
+		return func(value reflect.Value) {
+			value.SetString(strings.Repeat(value.String(), len(params)))
+		}, nil
+	})
+
+	value := "ab"
+	assert.NoError(t, Var(&value, "test_repeat=x y"))
+	assert.Equal(t, "abab", value)
+}
@@ -0,0 +1,195 @@
+package modifiers
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+const TagModify = "mod"
+
+// Modifier of struct field parsed from tag
+type fieldModifier struct {
+	index  []int
+	modify Modifier
+}
+
+// Parsed modifiers of struct type or error of its tags
+type structModifiersResult struct {
+	modifiers []fieldModifier
+	err       error
+}
+
+var tagCache sync.Map
+
+func clearTagCache() {
+	tagCache.Range(func(key, _ any) bool {
+		tagCache.Delete(key)
+		return true
+	})
+}
+
+// Apply modifiers of "mod" tags to fields of struct by pointer. Nested structs, pointers to structs
+// and structs in slices and arrays are modified too. Structs in maps are not modified.
+//
+// Panics if value is not a pointer or tag is not valid, see ParseTag. Tags are parsed once per type,
+// use Compile to check them at startup.
+//
+// Example:
+//
+//	type CreateUserRequest struct {
+//		Email string   `json:"email" mod:"trim,lower"`
+//		Name  string   `json:"name" mod:"strip_html,collapse_spaces,trim,unicode_nfc"`
+//		Tags  []string `json:"tags" mod:"trim,lower"`
+//		Limit int      `json:"limit" mod:"default=10"`
+//	}
+//
+//	modifiers.Struct(&req)
+func Struct(pointer any) {
+	value := reflect.ValueOf(pointer)
+	if value.Kind() != reflect.Pointer {
+		panic(fmt.Sprintf("modifiers: %T is not a pointer", pointer))
+	}
+	walk(value)
+}
+
+// Parse "mod" tags of struct and its nested structs. Returns error of the first tag which is not valid.
+// Parsed tags are cached, so services can call it at startup to fail before the first request.
+//
+// Example:
+//
+//	if err := modifiers.Compile(CreateUserRequest{}); err != nil {
+//		log.Fatal(err)
+//	}
+func Compile(value any) error {
+	return compileType(reflect.TypeOf(value), make(map[reflect.Type]bool))
+}
+
+func compileType(t reflect.Type, seen map[reflect.Type]bool) error {
+	for t != nil && (t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct || seen[t] {
+		return nil
+	}
+	seen[t] = true
+
+	modifiers, err := structModifiers(t)
+	if err != nil {
+		return err
+	}
+	for _, field := range modifiers {
+		if err := compileType(t.FieldByIndex(field.index).Type, seen); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Apply modifiers to fields of structs in value and its nested values
+func walk(value reflect.Value) {
+	for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return
+		}
+		value = value.Elem()
+	}
+
+	switch value.Kind() {
+	case reflect.Struct:
+		modifiers, err := structModifiers(value.Type())
+		if err != nil {
+			panic("modifiers: " + err.Error())
+		}
+		for _, field := range modifiers {
+			fieldValue, ok := fieldByIndex(value, field.index)
+			if !ok || !fieldValue.CanSet() {
+				continue
+			}
+			if field.modify != nil {
+				field.modify(fieldValue)
+			}
+			walk(fieldValue)
+		}
+	case reflect.Slice, reflect.Array:
+		if !containsStructs(value.Type().Elem()) {
+			return
+		}
+		for i := 0; i < value.Len(); i++ {
+			walk(value.Index(i))
+		}
+	}
+}
+
+// Type may contain structs with fields to modify
+func containsStructs(t reflect.Type) bool {
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct || t.Kind() == reflect.Interface
+}
+
+// Field of struct by index. Returns false if field is in nil embedded pointer
+func fieldByIndex(value reflect.Value, index []int) (reflect.Value, bool) {
+	for i, fieldIndex := range index {
+		if i > 0 && value.Kind() == reflect.Pointer {
+			if value.IsNil() {
+				return value, false
+			}
+			value = value.Elem()
+		}
+		value = value.Field(fieldIndex)
+	}
+	return value, true
+}
+
+// Modifiers of fields of struct type including fields of embedded structs
+func structModifiers(t reflect.Type) ([]fieldModifier, error) {
+	if cached, ok := tagCache.Load(t); ok {
+		result := cached.(structModifiersResult)
+		return result.modifiers, result.err
+	}
+	modifiers, err := collectModifiers(t, nil)
+	tagCache.Store(t, structModifiersResult{modifiers: modifiers, err: err})
+	return modifiers, err
+}
+
+func collectModifiers(t reflect.Type, parent []int) ([]fieldModifier, error) {
+	var modifiers []fieldModifier
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		index := append(append([]int{}, parent...), i)
+		tag := strings.TrimSpace(field.Tag.Get(TagModify))
+		if tag == "-" {
+			continue
+		}
+
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+		if field.Anonymous && fieldType.Kind() == reflect.Struct && tag == "" {
+			embedded, err := collectModifiers(fieldType, index)
+			if err != nil {
+				return nil, err
+			}
+			modifiers = append(modifiers, embedded...)
+			continue
+		}
+		if !field.IsExported() || (tag == "" && !containsStructs(field.Type)) {
+			continue
+		}
+
+		var modify Modifier
+		if tag != "" {
+			parsed, err := ParseTag(field.Type, tag)
+			if err != nil {
+				return nil, fmt.Errorf("field %s of %s: %w", field.Name, t, err)
+			}
+			modify = parsed
+		}
+		modifiers = append(modifiers, fieldModifier{index: index, modify: modify})
+	}
+	return modifiers, nil
+}
@@ -0,0 +1,91 @@
+package modifiers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testAddress struct {
+	City    string `json:"city" mod:"trim"`
+	Country string `json:"country" mod:"trim,upper,default=DE"`
+}
+
+type testBase struct {
+	ID string `json:"id" mod:"trim,lower"`
+}
+
+type testRequest struct {
+	testBase
+	Email     string            `json:"email" mod:"trim,lower"`
+	Name      *string           `json:"name" mod:"strip_html,collapse_spaces,trim"`
+	Tags      []string          `json:"tags" mod:"trim,lower"`
+	Limit     int               `json:"limit" mod:"default=10"`
+	Address   *testAddress      `json:"address"`
+	Items     []testAddress     `json:"items"`
+	Labels    map[string]string `json:"labels"`
+	Raw       string            `json:"raw" mod:"-"`
+	Untouched string            `json:"untouched"`
+}
+
+func TestStruct(t *testing.T) {
+	name := " <b>John</b>   Doe "
+	req := testRequest{
+		testBase: testBase{ID: " ABC "},
+		Email:    " Foo@Example.com ",
+		Name:     &name,
+		Tags:     []string{" Go ", "API"},
+		Address:  &testAddress{City: " Berlin "},
+		Items:    []testAddress{{City: " Paris ", Country: "fr"}},
+		Raw:      " raw ",
+	}
+
+	Struct(&req)
+
+	assert.Equal(t, "abc", req.ID)
+	assert.Equal(t, "foo@example.com", req.Email)
+	assert.Equal(t, "John Doe", name)
+	assert.Equal(t, []string{"go", "api"}, req.Tags)
+	assert.Equal(t, 10, req.Limit)
+	assert.Equal(t, testAddress{City: "Berlin", Country: "DE"}, *req.Address)
+	assert.Equal(t, []testAddress{{City: "Paris", Country: "FR"}}, req.Items)
+	assert.Equal(t, " raw ", req.Raw)
+}
+
+func TestStructPointer(t *testing.T) {
+	req := &testRequest{Email: " A@B.C "}
+	Struct(&req)
+	assert.Equal(t, "a@b.c", req.Email)
+
+	var nilRequest *testRequest
+	assert.NotPanics(t, func() { Struct(&nilRequest) })
+}
+
+func TestStructPanics(t *testing.T) {
+	assert.Panics(t, func() { Struct(testRequest{}) })
+	assert.Panics(t, func() {
+		Struct(&struct {
+			Count int `mod:"trim"`
+		}{})
+	})
+}
+
+func TestCompile(t *testing.T) {
+	assert.NoError(t, Compile(testRequest{}))
+	assert.NoError(t, Compile(nil))
+
+	type item struct {
+		Count int `mod:"trim"`
+	}
+	type request struct {
+		Name  string  `mod:"trim"`
+		Items []*item `json:"items"`
+	}
+	err := Compile(&request{})
+	assert.ErrorIs(t, err, ErrNotValidModifier)
+	assert.ErrorContains(t, err, "field Count")
+
+	assert.ErrorIs(t, Compile(struct {
+		Name string `mod:"unknown"`
+	}{}), ErrUnknownModifier)
+}
@@ -0,0 +1,124 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// Interval of removing expired entries from MemoryStore
+const memorySweepInterval = time.Minute
+
+type memoryEntry struct {
+	tokens  float64
+	last    time.Time
+	hits    []time.Time
+	expires time.Time
+}
+
+// MemoryStore keeps limits in memory of the current process
+type MemoryStore struct {
+	entries   map[string]*memoryEntry
+	lastSweep time.Time
+	mu        sync.Mutex
+}
+
+// Create new in-memory Store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		entries: make(map[string]*memoryEntry),
+	}
+}
+
+func (m *MemoryStore) TokenBucket(ctx context.Context, key string, rate Rate, now time.Time) (*Result, error) {
+	if err := rate.Validate(); err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sweep(now)
+
+	limit := float64(rate.Limit)
+	perNano := limit / float64(rate.Period)
+
+	entry, ok := m.entries[key]
+	if !ok {
+		entry = &memoryEntry{tokens: limit, last: now}
+		m.entries[key] = entry
+	}
+
+	if elapsed := now.Sub(entry.last); elapsed > 0 {
+		entry.tokens = math.Min(limit, entry.tokens+float64(elapsed)*perNano)
+		entry.last = now
+	}
+
+	result := &Result{
+		Limit: rate.Limit,
+	}
+	if entry.tokens >= 1 {
+		entry.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = time.Duration(math.Ceil((1 - entry.tokens) / perNano))
+	}
+	result.Remaining = int(entry.tokens)
+	result.ResetAfter = time.Duration(math.Ceil((limit - entry.tokens) / perNano))
+	entry.expires = now.Add(result.ResetAfter)
+
+	return result, nil
+}
+
+func (m *MemoryStore) SlidingWindow(ctx context.Context, key string, rate Rate, now time.Time) (*Result, error) {
+	if err := rate.Validate(); err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sweep(now)
+
+	entry, ok := m.entries[key]
+	if !ok {
+		entry = &memoryEntry{}
+		m.entries[key] = entry
+	}
+
+	start := now.Add(-rate.Period)
+	i := 0
+	for i < len(entry.hits) && !entry.hits[i].After(start) {
+		i++
+	}
+	entry.hits = entry.hits[i:]
+
+	result := &Result{
+		Limit: rate.Limit,
+	}
+	if len(entry.hits) < rate.Limit {
+		entry.hits = append(entry.hits, now)
+		result.Allowed = true
+	} else {
+		result.RetryAfter = entry.hits[0].Add(rate.Period).Sub(now)
+	}
+	result.Remaining = rate.Limit - len(entry.hits)
+	if len(entry.hits) > 0 {
+		entry.expires = entry.hits[len(entry.hits)-1].Add(rate.Period)
+		result.ResetAfter = entry.expires.Sub(now)
+	}
+
+	return result, nil
+}
+
+// Remove expired entries. Should be called with locked mutex
+func (m *MemoryStore) sweep(now time.Time) {
+	if now.Sub(m.lastSweep) < memorySweepInterval {
+		return
+	}
+	m.lastSweep = now
+	for key, entry := range m.entries {
+		if !entry.expires.After(now) {
+			delete(m.entries, key)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryStore_TokenBucket(t *testing.T) {
+	store := NewMemoryStore()
+	rate := Rate{Limit: 2, Period: time.Second}
+	now := time.Now()
+	ctx := context.Background()
+
+	result, err := store.TokenBucket(ctx, "key", rate, now)
+	assert.NoError(t, err)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 1, result.Remaining)
+	assert.Equal(t, 500*time.Millisecond, result.ResetAfter)
+
+	result, _ = store.TokenBucket(ctx, "key", rate, now)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+
+	result, _ = store.TokenBucket(ctx, "key", rate, now)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, 500*time.Millisecond, result.RetryAfter)
+	assert.Equal(t, time.Second, result.ResetAfter)
+
+	result, _ = store.TokenBucket(ctx, "other", rate, now)
+	assert.True(t, result.Allowed)
+
+	result, _ = store.TokenBucket(ctx, "key", rate, now.Add(500*time.Millisecond))
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+}
+
+func TestMemoryStore_SlidingWindow(t *testing.T) {
+	store := NewMemoryStore()
+	rate := Rate{Limit: 2, Period: time.Second}
+	now := time.Now()
+	ctx := context.Background()
+
+	result, err := store.SlidingWindow(ctx, "key", rate, now)
+	assert.NoError(t, err)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 1, result.Remaining)
+
+	result, _ = store.SlidingWindow(ctx, "key", rate, now.Add(400*time.Millisecond))
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+	assert.Equal(t, time.Second, result.ResetAfter)
+
+	result, _ = store.SlidingWindow(ctx, "key", rate, now.Add(600*time.Millisecond))
+	assert.False(t, result.Allowed)
+	assert.Equal(t, 400*time.Millisecond, result.RetryAfter)
+
+	result, _ = store.SlidingWindow(ctx, "key", rate, now.Add(time.Second+time.Millisecond))
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+}
+
+func TestMemoryStore_Sweep(t *testing.T) {
+	store := NewMemoryStore()
+	rate := Rate{Limit: 1, Period: time.Second}
+	now := time.Now()
+
+	store.TokenBucket(context.Background(), "first", rate, now)
+	store.TokenBucket(context.Background(), "second", rate, now.Add(2*memorySweepInterval))
+
+	assert.Len(t, store.entries, 1)
+	assert.Contains(t, store.entries, "second")
+}
@@ -0,0 +1,330 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Moranilt/http-utils/logger"
+	"github.com/Moranilt/http-utils/response"
+	"github.com/Moranilt/http-utils/tiny_errors"
+)
+
+const (
+	ERR_CODE_TooManyRequests = 998
+)
+
+const (
+	ErrTooManyRequests = "too many requests"
+)
+
+func init() {
+	tiny_errors.MustRegister(tiny_errors.Definition{
+		Code:        ERR_CODE_TooManyRequests,
+		Name:        "TooManyRequests",
+		Message:     ErrTooManyRequests,
+		HTTPStatus:  http.StatusTooManyRequests,
+		Severity:    tiny_errors.SeverityWarning,
+		Description: "Rate limit is exceeded. Retry after the number of seconds in Retry-After header",
+	})
+}
+
+var ErrNotValidRate = errors.New("not valid rate")
+
+const (
+	HeaderLimit      = "RateLimit-Limit"
+	HeaderRemaining  = "RateLimit-Remaining"
+	HeaderReset      = "RateLimit-Reset"
+	HeaderRetryAfter = "Retry-After"
+)
+
+type Algorithm string
+
+const (
+	// Bucket with capacity of Rate.Limit tokens, refilled by Rate.Limit tokens every Rate.Period
+	TokenBucket Algorithm = "token_bucket"
+
+	// No more than Rate.Limit requests during any Rate.Period
+	SlidingWindow Algorithm = "sliding_window"
+)
+
+// Amount of requests allowed during period
+type Rate struct {
+	Limit  int
+	Period time.Duration
+}
+
+// Returns ErrNotValidRate if limit or period is not positive
+func (r Rate) Validate() error {
+	if r.Limit <= 0 || r.Period <= 0 {
+		return fmt.Errorf("%w: limit and period should be positive, got %d per %s", ErrNotValidRate, r.Limit, r.Period)
+	}
+	return nil
+}
+
+// Result of taking a request from the limiter
+type Result struct {
+	// Request is allowed
+	Allowed bool
+
+	// Max amount of requests
+	Limit int
+
+	// Amount of requests left
+	Remaining int
+
+	// Time until the limit is fully restored
+	ResetAfter time.Duration
+
+	// Time until the next request will be allowed. Zero if request is allowed
+	RetryAfter time.Duration
+}
+
+// Store keeps state of limits. Implementations must be safe for concurrent use
+// and take a request atomically.
+type Store interface {
+	TokenBucket(ctx context.Context, key string, rate Rate, now time.Time) (*Result, error)
+	SlidingWindow(ctx context.Context, key string, rate Rate, now time.Time) (*Result, error)
+}
+
+// Returns a key which is used to identify the client
+type KeyFunc func(r *http.Request) string
+
+// Use IP address of the client as a key. By default it is the address of the connection.
+//
+// X-Forwarded-For and X-Real-IP headers are used only if the request is sent by one of
+// trustedProxies, which are IP addresses or CIDR ranges, e.g. "10.0.0.0/8". The client is the
+// last address of X-Forwarded-For which is not a trusted proxy, so clients can't choose their key.
+//
+// Panics if address of proxy is not valid.
+func KeyByIP(trustedProxies ...string) KeyFunc {
+	proxies := make([]netip.Prefix, len(trustedProxies))
+	for i, proxy := range trustedProxies {
+		prefix, err := parseProxy(proxy)
+		if err != nil {
+			panic("ratelimit: not valid trusted proxy " + strconv.Quote(proxy))
+		}
+		proxies[i] = prefix
+	}
+
+	return func(r *http.Request) string {
+		ip := remoteIP(r)
+		if !isTrusted(ip, proxies) {
+			return ip
+		}
+
+		forwarded := r.Header.Values("X-Forwarded-For")
+		var hops []string
+		for _, value := range forwarded {
+			for _, hop := range strings.Split(value, ",") {
+				if hop = strings.TrimSpace(hop); hop != "" {
+					hops = append(hops, hop)
+				}
+			}
+		}
+		for i := len(hops) - 1; i >= 0; i-- {
+			if !isTrusted(hops[i], proxies) {
+				return hops[i]
+			}
+		}
+		if len(hops) > 0 {
+			return hops[0]
+		}
+		if realIP := strings.TrimSpace(r.Header.Get("X-Real-IP")); realIP != "" {
+			return realIP
+		}
+		return ip
+	}
+}
+
+// Use value of header as a key. If header is missing, IP address of the connection is used,
+// so clients without header do not share one limit
+func KeyByHeader(name string) KeyFunc {
+	return func(r *http.Request) string {
+		if value := r.Header.Get(name); value != "" {
+			return value
+		}
+		return remoteIP(r)
+	}
+}
+
+// IP address of the connection without port
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+func parseProxy(proxy string) (netip.Prefix, error) {
+	if strings.Contains(proxy, "/") {
+		prefix, err := netip.ParsePrefix(proxy)
+		return prefix.Masked(), err
+	}
+	addr, err := netip.ParseAddr(proxy)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+func isTrusted(ip string, proxies []netip.Prefix) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, proxy := range proxies {
+		if proxy.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+type Limiter struct {
+	store     Store
+	algorithm Algorithm
+	rate      Rate
+	key       KeyFunc
+	prefix    string
+	logger    logger.Logger
+}
+
+// Option is a function type that can be used to configure a Limiter.
+type Option func(*Limiter)
+
+// Set the function which returns a key of the client. Default is KeyByIP
+func WithKey(key KeyFunc) Option {
+	return func(l *Limiter) {
+		l.key = key
+	}
+}
+
+// Use IP address of the client as a key and read it from X-Forwarded-For and X-Real-IP
+// headers of requests sent by trusted proxies, see KeyByIP
+func WithTrustedProxies(proxies ...string) Option {
+	return func(l *Limiter) {
+		l.key = KeyByIP(proxies...)
+	}
+}
+
+// Set prefix of keys in store. Should be used to separate limiters with the same store
+func WithPrefix(prefix string) Option {
+	return func(l *Limiter) {
+		l.prefix = prefix
+	}
+}
+
+// Set logger to report store errors. Default is logger.Default()
+func WithLogger(log logger.Logger) Option {
+	return func(l *Limiter) {
+		l.logger = log
+	}
+}
+
+// Create new Limiter.
+//
+// Panics if rate is not valid, see Rate.Validate
+func New(store Store, algorithm Algorithm, rate Rate, options ...Option) *Limiter {
+	if err := rate.Validate(); err != nil {
+		panic("ratelimit: " + err.Error())
+	}
+	l := &Limiter{
+		store:     store,
+		algorithm: algorithm,
+		rate:      rate,
+		key:       KeyByIP(),
+		prefix:    "ratelimit",
+		logger:    logger.Default(),
+	}
+
+	for _, opt := range options {
+		opt(l)
+	}
+
+	return l
+}
+
+// Take a request for the key
+func (l *Limiter) Allow(ctx context.Context, key string) (*Result, error) {
+	key = l.prefix + ":" + key
+	now := time.Now()
+	if l.algorithm == SlidingWindow {
+		return l.store.SlidingWindow(ctx, key, l.rate, now)
+	}
+	return l.store.TokenBucket(ctx, key, l.rate, now)
+}
+
+// Take a request for the key and return an error if the limit is exceeded.
+//
+// If the store returns an error, it is logged and the request is allowed.
+// Headers are written to w if it is not nil.
+func (l *Limiter) Check(ctx context.Context, w http.ResponseWriter, key string) tiny_errors.ErrorHandler {
+	result, err := l.Allow(ctx, key)
+	if err != nil {
+		l.logger.Error("rate limit store error", "error", err.Error(), "key", key)
+		return nil
+	}
+
+	if w != nil {
+		SetHeaders(w, result)
+	}
+
+	if !result.Allowed {
+		return tiny_errors.New(
+			ERR_CODE_TooManyRequests,
+			tiny_errors.Message(ErrTooManyRequests),
+			tiny_errors.HTTPStatus(http.StatusTooManyRequests),
+		)
+	}
+	return nil
+}
+
+// Key of the client for request
+func (l *Limiter) Key(r *http.Request) string {
+	return l.key(r)
+}
+
+// Middleware limits requests using key of the limiter and responds with
+// status 429 when the limit is exceeded.
+//
+// Example:
+//
+//	limiter := ratelimit.New(ratelimit.NewMemoryStore(), ratelimit.TokenBucket, ratelimit.Rate{Limit: 10, Period: time.Second})
+//	router.Use(limiter.Middleware)
+func (l *Limiter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := l.Check(r.Context(), w, l.Key(r)); err != nil {
+			response.ErrorResponse(w, err, err.GetHTTPStatus(), response.Request(r))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Set RateLimit-* headers and Retry-After if request is not allowed
+func SetHeaders(w http.ResponseWriter, result *Result) {
+	w.Header().Set(HeaderLimit, strconv.Itoa(result.Limit))
+	w.Header().Set(HeaderRemaining, strconv.Itoa(max(result.Remaining, 0)))
+	w.Header().Set(HeaderReset, strconv.Itoa(seconds(result.ResetAfter)))
+	if !result.Allowed {
+		w.Header().Set(HeaderRetryAfter, strconv.Itoa(seconds(result.RetryAfter)))
+	}
+}
+
+// Round duration up to seconds
+func seconds(d time.Duration) int {
+	if d <= 0 {
+		return 0
+	}
+	return int(math.Ceil(d.Seconds()))
+}
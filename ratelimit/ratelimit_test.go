@@ -0,0 +1,174 @@
+package ratelimit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Moranilt/http-utils/logger"
+	"github.com/Moranilt/http-utils/response"
+	"github.com/Moranilt/http-utils/tiny_errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type failingStore struct{}
+
+func (failingStore) TokenBucket(ctx context.Context, key string, rate Rate, now time.Time) (*Result, error) {
+	return nil, errors.New("store is down")
+}
+
+func (failingStore) SlidingWindow(ctx context.Context, key string, rate Rate, now time.Time) (*Result, error) {
+	return nil, errors.New("store is down")
+}
+
+func TestLimiter_Middleware(t *testing.T) {
+	limiter := New(NewMemoryStore(), SlidingWindow, Rate{Limit: 1, Period: time.Minute}, WithKey(KeyByHeader("X-Api-Key")))
+	handler := limiter.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	makeRequest := func(apiKey string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("X-Api-Key", apiKey)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := makeRequest("first")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "1", rec.Header().Get(HeaderLimit))
+	assert.Equal(t, "0", rec.Header().Get(HeaderRemaining))
+	assert.Equal(t, "60", rec.Header().Get(HeaderReset))
+	assert.Empty(t, rec.Header().Get(HeaderRetryAfter))
+
+	rec = makeRequest("first")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "60", rec.Header().Get(HeaderRetryAfter))
+
+	var resp response.DefaultResponse[any, *tiny_errors.Error]
+	err := json.NewDecoder(rec.Body).Decode(&resp)
+	assert.NoError(t, err)
+	assert.Equal(t, ERR_CODE_TooManyRequests, resp.Error.Code)
+	assert.Equal(t, ErrTooManyRequests, resp.Error.Message)
+
+	rec = makeRequest("second")
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestLimiter_StoreError(t *testing.T) {
+	limiter := New(failingStore{}, TokenBucket, Rate{Limit: 1, Period: time.Minute}, WithLogger(logger.NewMock()))
+	handler := limiter.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Header().Get(HeaderLimit))
+}
+
+func TestLimiter_Prefix(t *testing.T) {
+	store := NewMemoryStore()
+	rate := Rate{Limit: 1, Period: time.Minute}
+	first := New(store, TokenBucket, rate, WithPrefix("first"))
+	second := New(store, TokenBucket, rate, WithPrefix("second"))
+
+	result, _ := first.Allow(context.Background(), "key")
+	assert.True(t, result.Allowed)
+	result, _ = second.Allow(context.Background(), "key")
+	assert.True(t, result.Allowed)
+	result, _ = first.Allow(context.Background(), "key")
+	assert.False(t, result.Allowed)
+}
+
+func TestRate_Validate(t *testing.T) {
+	assert.NoError(t, Rate{Limit: 1, Period: time.Second}.Validate())
+	assert.ErrorIs(t, Rate{Limit: 0, Period: time.Second}.Validate(), ErrNotValidRate)
+	assert.ErrorIs(t, Rate{Limit: 1}.Validate(), ErrNotValidRate)
+	assert.ErrorIs(t, Rate{Limit: -1, Period: -time.Second}.Validate(), ErrNotValidRate)
+
+	assert.Panics(t, func() {
+		New(NewMemoryStore(), TokenBucket, Rate{Limit: 10})
+	})
+
+	_, err := NewMemoryStore().TokenBucket(context.Background(), "key", Rate{Period: time.Second}, time.Now())
+	assert.ErrorIs(t, err, ErrNotValidRate)
+	_, err = NewMemoryStore().SlidingWindow(context.Background(), "key", Rate{Limit: 1}, time.Now())
+	assert.ErrorIs(t, err, ErrNotValidRate)
+}
+
+func TestKeyByHeader(t *testing.T) {
+	key := KeyByHeader("X-Api-Key")
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	assert.Equal(t, "10.0.0.1", key(req))
+
+	req.Header.Set("X-Api-Key", "secret")
+	assert.Equal(t, "secret", key(req))
+}
+
+func TestKeyByIP(t *testing.T) {
+	makeRequest := func(remoteAddr string, headers map[string]string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = remoteAddr
+		for name, value := range headers {
+			req.Header.Set(name, value)
+		}
+		return req
+	}
+
+	key := KeyByIP()
+	assert.Equal(t, "203.0.113.1", key(makeRequest("203.0.113.1:1234", map[string]string{
+		"X-Forwarded-For": "198.51.100.7",
+		"X-Real-IP":       "198.51.100.8",
+	})))
+
+	key = KeyByIP("10.0.0.0/8", "192.168.1.1")
+	assert.Equal(t, "198.51.100.7", key(makeRequest("10.0.0.2:1234", map[string]string{
+		"X-Forwarded-For": "1.1.1.1, 198.51.100.7, 192.168.1.1",
+	})))
+	assert.Equal(t, "198.51.100.8", key(makeRequest("10.0.0.2:1234", map[string]string{
+		"X-Real-IP": "198.51.100.8",
+	})))
+	assert.Equal(t, "10.0.0.2", key(makeRequest("10.0.0.2:1234", nil)))
+	assert.Equal(t, "203.0.113.1", key(makeRequest("203.0.113.1:1234", map[string]string{
+		"X-Forwarded-For": "198.51.100.7",
+	})))
+
+	assert.Panics(t, func() { KeyByIP("not an ip") })
+}
+
+func TestLimiter_SpoofedForwardedFor(t *testing.T) {
+	limiter := New(NewMemoryStore(), TokenBucket, Rate{Limit: 1, Period: time.Minute})
+	handler := limiter.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	makeRequest := func(forwardedFor string) int {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = "203.0.113.1:1234"
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusOK, makeRequest("198.51.100.1"))
+	assert.Equal(t, http.StatusTooManyRequests, makeRequest("198.51.100.2"))
+	assert.Equal(t, http.StatusTooManyRequests, makeRequest("198.51.100.3"))
+}
+
+func TestLimiter_TrustedProxies(t *testing.T) {
+	limiter := New(NewMemoryStore(), TokenBucket, Rate{Limit: 1, Period: time.Minute}, WithTrustedProxies("10.0.0.1"))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	req.Header.Set("X-Forwarded-For", "198.51.100.1")
+	assert.Equal(t, "198.51.100.1", limiter.Key(req))
+}
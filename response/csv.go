@@ -0,0 +1,373 @@
+package response
+
+import (
+	"context"
+	"encoding"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	go_json "github.com/goccy/go-json"
+)
+
+const (
+	ContentTypeCSV = "text/csv"
+	defaultComma   = ','
+)
+
+// Rows can not be written as CSV. Returned before anything is written to the response
+var ErrNotTabular = errors.New("response is not a slice of structs")
+
+var (
+	utf8BOM     = []byte{0xEF, 0xBB, 0xBF}
+	timeType    = reflect.TypeFor[time.Time]()
+	textMarshal = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+// Set delimiter of CSV columns. Default is comma
+func CSVDelimiter(delimiter rune) Option {
+	return func(c *config) {
+		c.csv.delimiter = delimiter
+	}
+}
+
+// Write UTF-8 byte order mark before CSV, so Excel detects encoding
+func CSVBOM() Option {
+	return func(c *config) {
+		c.csv.bom = true
+	}
+}
+
+// Set layout of time.Time values. Default is time.RFC3339
+func CSVTimeFormat(layout string) Option {
+	return func(c *config) {
+		c.csv.timeLayout = layout
+	}
+}
+
+// Set format and precision of floats as in strconv.FormatFloat. Default is 'f' with the smallest precision
+func CSVFloatFormat(format byte, precision int) Option {
+	return func(c *config) {
+		c.csv.floatFormat = format
+		c.csv.floatPrecision = precision
+	}
+}
+
+// Set value of nil pointers, slices, maps and interfaces. Default is empty string
+func CSVNil(value string) Option {
+	return func(c *config) {
+		c.csv.nilValue = value
+	}
+}
+
+// Write cells starting with =, +, -, @, tab or carriage return as they are.
+// By default such cells are prefixed with ' so spreadsheets do not run them as formulas
+func CSVRawFormulas() Option {
+	return func(c *config) {
+		c.csv.rawFormulas = true
+	}
+}
+
+// Send CSV as attachment with file name
+func CSVFileName(name string) Option {
+	return func(c *config) {
+		c.csv.fileName = name
+	}
+}
+
+type csvConfig struct {
+	delimiter      rune
+	bom            bool
+	timeLayout     string
+	floatFormat    byte
+	floatPrecision int
+	nilValue       string
+	fileName       string
+	rawFormulas    bool
+}
+
+func defaultCSVConfig() csvConfig {
+	return csvConfig{
+		delimiter:      defaultComma,
+		timeLayout:     time.RFC3339,
+		floatFormat:    'f',
+		floatPrecision: -1,
+	}
+}
+
+// Client prefers CSV to JSON by Accept header
+func WantsCSV(r *http.Request) bool {
+	csvQuality := mediaTypeQuality(r, ContentTypeCSV)
+	return csvQuality > 0 && csvQuality >= mediaTypeQuality(r, ContentTypeJSON)
+}
+
+// Write slice or array of structs as CSV. Pointers to slices and structs are supported.
+//
+// Names of columns are taken from csv tags, then from json tags, then from names of fields.
+// Fields with tag "-" are skipped. Fields of embedded structs without tags are flattened.
+//
+// Returns ErrNotTabular if rows is not a slice of structs. In this case nothing is written.
+//
+// WithFields option selects columns by json names of fields. Nested fields, e.g. "owner.email",
+// filter JSON of the cell.
+//
+// Example:
+//
+//	type User struct {
+//		Id        int       `csv:"ID"`
+//		Name      string    `json:"name"`
+//		CreatedAt time.Time `csv:"Created At"`
+//		Password  string    `csv:"-"`
+//	}
+//
+//	response.CSV(w, http.StatusOK, users, response.CSVFileName("users.csv"), response.CSVBOM())
+func CSV(w http.ResponseWriter, status int, rows any, options ...Option) error {
+	value := reflect.ValueOf(rows)
+	for value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return ErrNotTabular
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+		return ErrNotTabular
+	}
+
+	var i int
+	return writeCSV(w, status, value.Type().Elem(), func(ctx context.Context) (reflect.Value, bool, error) {
+		if i >= value.Len() {
+			return reflect.Value{}, false, nil
+		}
+		i++
+		return value.Index(i - 1), true, nil
+	}, options)
+}
+
+// Write stream of structs as CSV. Streaming stops when the client disconnects
+// or pull returns an error. Returns the error which stopped the stream.
+//
+// Returns ErrNotTabular if T is not a struct. In this case nothing is written.
+func CSVStream[T any](w http.ResponseWriter, status int, pull PullFunc[T], options ...Option) error {
+	return writeCSV(w, status, reflect.TypeFor[T](), func(ctx context.Context) (reflect.Value, bool, error) {
+		item, ok, err := pull(ctx)
+		return reflect.ValueOf(&item).Elem(), ok, err
+	}, options)
+}
+
+func writeCSV(w http.ResponseWriter, status int, rowType reflect.Type, pull PullFunc[reflect.Value], options []Option) error {
+	columns, err := csvColumns(rowType)
+	if err != nil {
+		return err
+	}
+
+	s := newStream(w, options)
+	if s.cfg.fields != nil {
+		columns = selectColumns(columns, s.cfg.fields)
+	}
+	if s.cfg.csv.fileName != "" {
+		w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": s.cfg.csv.fileName}))
+	}
+	s.writeHeader(ContentTypeCSV, status)
+	if s.cfg.csv.bom {
+		if err := s.writeRaw(utf8BOM); err != nil {
+			return err
+		}
+	}
+
+	writer := csv.NewWriter(s)
+	writer.Comma = s.cfg.csv.delimiter
+	s.beforeFlush = writer.Flush
+
+	record := make([]string, len(columns))
+	for i, column := range columns {
+		record[i] = column.name
+	}
+	if err := writer.Write(record); err != nil {
+		return err
+	}
+
+	err = runStream(s, pull, func(row reflect.Value) error {
+		for i, column := range columns {
+			record[i] = s.cfg.csv.format(fieldByIndex(row, column.index), column.fields)
+		}
+		return writer.Write(record)
+	})
+	s.flush()
+	if err != nil && !s.disconnected(err) {
+		s.cfg.logger.Error(ErrStreamInterrupted, "error", err.Error())
+	}
+	return err
+}
+
+type csvColumn struct {
+	name     string
+	jsonName string
+	index    []int
+
+	// Nested fields selected in the cell. nil means the whole value
+	fields FieldSet
+}
+
+// Columns with json names from fields in order of columns
+func selectColumns(columns []csvColumn, fields FieldSet) []csvColumn {
+	var selected []csvColumn
+	for _, column := range columns {
+		children, ok := fields[column.jsonName]
+		if !ok {
+			continue
+		}
+		column.fields = children
+		selected = append(selected, column)
+	}
+	return selected
+}
+
+// Columns of CSV for struct type
+func csvColumns(t reflect.Type) ([]csvColumn, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || t == timeType {
+		return nil, ErrNotTabular
+	}
+
+	var columns []csvColumn
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, tagged := csvColumnName(field)
+		if name == "-" {
+			continue
+		}
+
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+		if field.Anonymous && !tagged && fieldType.Kind() == reflect.Struct {
+			embedded, err := csvColumns(fieldType)
+			if err != nil {
+				return nil, err
+			}
+			for _, column := range embedded {
+				column.index = append([]int{i}, column.index...)
+				columns = append(columns, column)
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		columns = append(columns, csvColumn{name: name, jsonName: jsonName(field), index: []int{i}})
+	}
+	return columns, nil
+}
+
+// Name of column from csv or json tag. tagged is false if name is taken from name of field
+func csvColumnName(field reflect.StructField) (name string, tagged bool) {
+	if tag, ok := field.Tag.Lookup("csv"); ok && tag != "" {
+		return tag, true
+	}
+	if tag, ok := field.Tag.Lookup("json"); ok {
+		name, _, _ := strings.Cut(tag, ",")
+		if name != "" {
+			return name, true
+		}
+	}
+	return field.Name, false
+}
+
+// Name of field in JSON
+func jsonName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
+
+// Field of struct by index. Returns invalid value if any embedded pointer is nil
+func fieldByIndex(row reflect.Value, index []int) reflect.Value {
+	for row.Kind() == reflect.Pointer {
+		if row.IsNil() {
+			return reflect.Value{}
+		}
+		row = row.Elem()
+	}
+	field, err := row.FieldByIndexErr(index)
+	if err != nil {
+		return reflect.Value{}
+	}
+	return field
+}
+
+// Format value of field. Values encoded to JSON are filtered by fields if they are not nil
+func (c csvConfig) format(value reflect.Value, fields FieldSet) string {
+	for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return c.nilValue
+		}
+		value = value.Elem()
+	}
+	if !value.IsValid() {
+		return c.nilValue
+	}
+
+	if value.Type() == timeType && value.CanInterface() {
+		return value.Interface().(time.Time).Format(c.timeLayout)
+	}
+	if value.Type().Implements(textMarshal) && value.CanInterface() {
+		text, err := value.Interface().(encoding.TextMarshaler).MarshalText()
+		if err == nil {
+			return c.escape(string(text))
+		}
+	}
+
+	switch value.Kind() {
+	case reflect.String:
+		return c.escape(value.String())
+	case reflect.Bool:
+		return strconv.FormatBool(value.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(value.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(value.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(value.Float(), c.floatFormat, c.floatPrecision, value.Type().Bits())
+	case reflect.Slice, reflect.Map:
+		if value.IsNil() {
+			return c.nilValue
+		}
+	}
+
+	if !value.CanInterface() {
+		return c.escape(fmt.Sprint(value))
+	}
+	data, err := go_json.Marshal(value.Interface())
+	if err != nil {
+		return c.escape(fmt.Sprint(value.Interface()))
+	}
+	if fields != nil {
+		if filtered, err := fields.filterJSON(data); err == nil {
+			data = filtered
+		}
+	}
+	return c.escape(string(data))
+}
+
+// Prefix text which spreadsheets treat as formula with '. Numbers are formatted without escaping
+func (c csvConfig) escape(text string) string {
+	if c.rawFormulas || text == "" {
+		return text
+	}
+	switch text[0] {
+	case '=', '+', '-', '@', '\t', '\r':
+		return "'" + text
+	}
+	return text
+}
@@ -0,0 +1,166 @@
+package response
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Moranilt/http-utils/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+type csvBase struct {
+	Id int `csv:"ID"`
+}
+
+type csvOwner struct {
+	Email string `json:"email"`
+}
+
+type csvRow struct {
+	csvBase
+	Name      string     `json:"name,omitempty"`
+	Price     float64    `csv:"Price"`
+	Active    bool       `csv:"Active"`
+	CreatedAt time.Time  `csv:"Created At"`
+	DeletedAt *time.Time `csv:"Deleted At"`
+	Owner     *csvOwner  `csv:"Owner"`
+	Tags      []string   `csv:"Tags"`
+	Password  string     `csv:"-"`
+	Comment   string
+	internal  string
+}
+
+func TestCSV(t *testing.T) {
+	createdAt := time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC)
+	rows := []*csvRow{
+		{
+			csvBase:   csvBase{Id: 1},
+			Name:      "Tom, Jr.",
+			Price:     10.5,
+			Active:    true,
+			CreatedAt: createdAt,
+			Owner:     &csvOwner{Email: "tom@example.com"},
+			Tags:      []string{"a", "b"},
+			Password:  "secret",
+			Comment:   "first",
+		},
+		{
+			csvBase:   csvBase{Id: 2},
+			Name:      "Ann",
+			Price:     3,
+			CreatedAt: createdAt,
+			DeletedAt: &createdAt,
+		},
+	}
+
+	t.Run("default format", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		err := CSV(rec, http.StatusOK, rows)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "text/csv; charset=utf-8", rec.Header().Get("Content-Type"))
+		assert.Equal(t, ""+
+			"ID,name,Price,Active,Created At,Deleted At,Owner,Tags,Comment\n"+
+			"1,\"Tom, Jr.\",10.5,true,2024-05-01T10:30:00Z,,\"{\"\"email\"\":\"\"tom@example.com\"\"}\",\"[\"\"a\"\",\"\"b\"\"]\",first\n"+
+			"2,Ann,3,false,2024-05-01T10:30:00Z,2024-05-01T10:30:00Z,,,\n",
+			rec.Body.String())
+	})
+
+	t.Run("options", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		err := CSV(rec, http.StatusOK, rows[1:],
+			CSVDelimiter(';'),
+			CSVBOM(),
+			CSVTimeFormat(time.DateOnly),
+			CSVFloatFormat('f', 2),
+			CSVNil("NULL"),
+			CSVFileName("rows.csv"),
+		)
+
+		assert.NoError(t, err)
+		assert.Equal(t, `attachment; filename=rows.csv`, rec.Header().Get("Content-Disposition"))
+		assert.Equal(t, "\xEF\xBB\xBF"+
+			"ID;name;Price;Active;Created At;Deleted At;Owner;Tags;Comment\n"+
+			"2;Ann;3.00;false;2024-05-01;2024-05-01;NULL;NULL;\n",
+			rec.Body.String())
+	})
+
+	t.Run("formulas", func(t *testing.T) {
+		type row struct {
+			Name  string `csv:"name"`
+			Price int    `csv:"price"`
+		}
+		rows := []row{{"=HYPERLINK(\"http://evil\")", -3}, {"+1", 1}, {"-1", 2}, {"@SUM(A1)", 3}, {"\tcmd", 4}, {"\rcmd", 5}, {"a=b", 6}}
+
+		rec := httptest.NewRecorder()
+		assert.NoError(t, CSV(rec, http.StatusOK, rows))
+		assert.Equal(t, ""+
+			"name,price\n"+
+			"\"'=HYPERLINK(\"\"http://evil\"\")\",-3\n"+
+			"'+1,1\n"+
+			"'-1,2\n"+
+			"'@SUM(A1),3\n"+
+			"'\tcmd,4\n"+
+			"\"'\rcmd\",5\n"+
+			"a=b,6\n",
+			rec.Body.String())
+
+		rec = httptest.NewRecorder()
+		assert.NoError(t, CSV(rec, http.StatusOK, rows[1:3], CSVRawFormulas()))
+		assert.Equal(t, "name,price\n+1,1\n-1,2\n", rec.Body.String())
+	})
+
+	t.Run("not tabular", func(t *testing.T) {
+		for _, rows := range []any{nil, "text", []string{"a"}, csvRow{}, []time.Time{}} {
+			rec := httptest.NewRecorder()
+			err := CSV(rec, http.StatusOK, rows)
+			assert.ErrorIs(t, err, ErrNotTabular)
+			assert.Empty(t, rec.Body.String())
+		}
+	})
+}
+
+func TestCSVStream(t *testing.T) {
+	type row struct {
+		Id   int    `csv:"id"`
+		Name string `csv:"name"`
+	}
+
+	rec := httptest.NewRecorder()
+	err := CSVStream(rec, http.StatusOK, FromSlice([]row{{1, "a"}, {2, "b"}}), FlushEvery(1))
+	assert.NoError(t, err)
+	assert.Equal(t, "id,name\n1,a\n2,b\n", rec.Body.String())
+	assert.True(t, rec.Flushed)
+
+	rec = httptest.NewRecorder()
+	pullErr := errors.New("database is unavailable")
+	var pulled bool
+	err = CSVStream(rec, http.StatusOK, func(ctx context.Context) (row, bool, error) {
+		if pulled {
+			return row{}, false, pullErr
+		}
+		pulled = true
+		return row{1, "a"}, true, nil
+	}, WithLogger(logger.NewMock()))
+	assert.ErrorIs(t, err, pullErr)
+	assert.Equal(t, "id,name\n1,a\n", rec.Body.String())
+}
+
+func TestWantsCSV(t *testing.T) {
+	for accept, expected := range map[string]bool{
+		"":                                 false,
+		"application/json":                 false,
+		"text/csv":                         true,
+		"text/csv, application/json":       true,
+		"application/json, text/csv;q=0.5": false,
+		"text/csv;q=0":                     false,
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Accept", accept)
+		assert.Equal(t, expected, WantsCSV(req), accept)
+	}
+}
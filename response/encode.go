@@ -0,0 +1,141 @@
+package response
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/Moranilt/http-utils/tiny_errors"
+	go_json "github.com/goccy/go-json"
+)
+
+const (
+	ERR_CODE_EncodingFailed = 991
+)
+
+const (
+	ErrEncodingFailed = "unable to encode response"
+)
+
+func init() {
+	tiny_errors.MustRegister(
+		tiny_errors.Definition{
+			Code:        ERR_CODE_EncodingFailed,
+			Name:        "EncodingFailed",
+			Message:     ErrEncodingFailed,
+			HTTPStatus:  http.StatusInternalServerError,
+			Description: "Response body can not be encoded",
+		},
+		tiny_errors.Definition{
+			Code:        ERR_CODE_StreamInterrupted,
+			Name:        "StreamInterrupted",
+			Message:     ErrStreamInterrupted,
+			HTTPStatus:  http.StatusInternalServerError,
+			Description: "Streamed response is interrupted by an error, received items are incomplete",
+		},
+		tiny_errors.Definition{
+			Code:        ERR_CODE_UnknownFields,
+			Name:        "UnknownFields",
+			Message:     ErrUnknownFields,
+			HTTPStatus:  http.StatusBadRequest,
+			Severity:    tiny_errors.SeverityWarning,
+			Description: "URL-query param \"fields\" contains fields which are not present in response",
+		},
+	)
+}
+
+const (
+	ContentTypeJSON = "application/json"
+	defaultCharset  = "utf-8"
+	prettyIndent    = "  "
+)
+
+// Content-Type header of response with charset
+func (c *config) contentTypeHeader(mediaType string) string {
+	if c.charset == "" {
+		return mediaType
+	}
+	return mediaType + "; charset=" + c.charset
+}
+
+// Indentation of JSON. Returns empty string if response should not be indented
+func (c *config) jsonIndent() string {
+	if c.indent != "" {
+		return c.indent
+	}
+	if c.prettyQuery && c.request != nil && c.request.URL.Query().Has("pretty") {
+		pretty, err := strconv.ParseBool(c.request.URL.Query().Get("pretty"))
+		if err != nil || pretty {
+			return prettyIndent
+		}
+	}
+	return ""
+}
+
+func (c *config) encoder(w io.Writer) *go_json.Encoder {
+	encoder := go_json.NewEncoder(w)
+	encoder.SetEscapeHTML(c.escapeHTML)
+	if indent := c.jsonIndent(); indent != "" {
+		encoder.SetIndent("", indent)
+	}
+	return encoder
+}
+
+// Encode payload and write it with status.
+//
+// If response is buffered and payload can not be encoded, clean error with status 500 is sent instead.
+func writeJSON(w http.ResponseWriter, status int, mediaType string, payload any, cfg *config) {
+	if !cfg.buffered {
+		w.Header().Set("Content-Type", cfg.contentTypeHeader(mediaType))
+		w.WriteHeader(status)
+		if err := cfg.encoder(w).Encode(payload); err != nil {
+			cfg.logger.Error(ErrEncodingFailed, "error", err.Error(), "status", status)
+		}
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := cfg.encoder(&buf).Encode(payload); err != nil {
+		cfg.logger.Error(ErrEncodingFailed, "error", err.Error(), "status", status)
+		writeEncodingError(w, cfg)
+		return
+	}
+
+	w.Header().Set("Content-Type", cfg.contentTypeHeader(mediaType))
+	w.WriteHeader(status)
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		cfg.logger.Error(ErrEncodingFailed, "error", err.Error(), "status", status)
+	}
+}
+
+// Send error with status 500 in format of the response
+func writeEncodingError(w http.ResponseWriter, cfg *config) {
+	err := localizeError(tiny_errors.New(
+		ERR_CODE_EncodingFailed,
+		tiny_errors.Message(ErrEncodingFailed),
+		tiny_errors.HTTPStatus(http.StatusInternalServerError),
+	), cfg)
+
+	mediaType := ContentTypeJSON
+	var payload any
+	switch {
+	case cfg.useProblem():
+		mediaType = ContentTypeProblemJSON
+		payload = NewProblem(err, err.GetHTTPStatus(), cfg.request)
+	case cfg.envelope != nil:
+		payload = cfg.envelope(nil, err, nil)
+	default:
+		payload = DefaultResponse[any, tiny_errors.ErrorHandler]{Error: err}
+	}
+
+	data, encodeErr := go_json.Marshal(payload)
+	if encodeErr != nil {
+		cfg.logger.Error(ErrEncodingFailed, "error", encodeErr.Error(), "status", err.GetHTTPStatus())
+		data = []byte(err.JSON())
+	}
+
+	w.Header().Set("Content-Type", cfg.contentTypeHeader(mediaType))
+	w.WriteHeader(err.GetHTTPStatus())
+	w.Write(append(data, '\n'))
+}
@@ -0,0 +1,95 @@
+package response
+
+import (
+	"bytes"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Moranilt/http-utils/logger"
+	"github.com/Moranilt/http-utils/tiny_errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContentType(t *testing.T) {
+	rec := httptest.NewRecorder()
+	SuccessResponse(rec, "ok", http.StatusOK)
+	assert.Equal(t, "application/json; charset=utf-8", rec.Header().Get("Content-Type"))
+
+	rec = httptest.NewRecorder()
+	SuccessResponse(rec, "ok", http.StatusOK, ContentType("application/vnd.api+json"), Charset(""))
+	assert.Equal(t, "application/vnd.api+json", rec.Header().Get("Content-Type"))
+
+	rec = httptest.NewRecorder()
+	ProblemResponse(rec, tiny_errors.New(1), http.StatusBadRequest)
+	assert.Equal(t, "application/problem+json; charset=utf-8", rec.Header().Get("Content-Type"))
+}
+
+func TestIndent(t *testing.T) {
+	body := map[string]int{"id": 1}
+
+	t.Run("pretty query", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		SuccessResponse(rec, body, http.StatusOK, Request(httptest.NewRequest(http.MethodGet, "/?pretty", nil)))
+		assert.Contains(t, rec.Body.String(), "\n  \"body\": {\n    \"id\": 1\n  }")
+	})
+
+	t.Run("pretty query is false", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		SuccessResponse(rec, body, http.StatusOK, Request(httptest.NewRequest(http.MethodGet, "/?pretty=false", nil)))
+		assert.Equal(t, `{"error":null,"body":{"id":1}}`+"\n", rec.Body.String())
+	})
+
+	t.Run("pretty query is disabled", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		SuccessResponse(rec, body, http.StatusOK, Request(httptest.NewRequest(http.MethodGet, "/?pretty", nil)), PrettyQuery(false))
+		assert.Equal(t, `{"error":null,"body":{"id":1}}`+"\n", rec.Body.String())
+	})
+
+	t.Run("indent", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		SuccessResponse(rec, body, http.StatusOK, Indent("\t"))
+		assert.Contains(t, rec.Body.String(), "\n\t\"body\": {\n\t\t\"id\": 1\n\t}")
+	})
+}
+
+func TestEscapeHTML(t *testing.T) {
+	rec := httptest.NewRecorder()
+	SuccessResponse(rec, "<b>&</b>", http.StatusOK)
+	assert.Contains(t, rec.Body.String(), `"\u003cb\u003e\u0026\u003c/b\u003e"`)
+
+	rec = httptest.NewRecorder()
+	SuccessResponse(rec, "<b>&</b>", http.StatusOK, EscapeHTML(false))
+	assert.Contains(t, rec.Body.String(), `"<b>&</b>"`)
+}
+
+func TestEncodingFailure(t *testing.T) {
+	t.Run("buffered", func(t *testing.T) {
+		logs := new(bytes.Buffer)
+		rec := httptest.NewRecorder()
+		SuccessResponse(rec, math.Inf(1), http.StatusOK, WithLogger(logger.New(logs, logger.TYPE_JSON)))
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		assert.JSONEq(t, `{"error":{"code":991,"message":"unable to encode response","details":null},"body":null}`, rec.Body.String())
+		assert.Contains(t, logs.String(), ErrEncodingFailed)
+	})
+
+	t.Run("buffered problem", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		Default(rec, math.Inf(1), tiny_errors.New(1), http.StatusBadRequest, WithErrorFormat(FormatProblem), WithLogger(logger.NewMock()))
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, ContentTypeProblemJSON, mediaType(rec))
+	})
+
+	t.Run("not buffered", func(t *testing.T) {
+		logs := new(bytes.Buffer)
+		rec := httptest.NewRecorder()
+		SuccessResponse(rec, math.Inf(1), http.StatusOK, Buffered(false), WithLogger(logger.New(logs, logger.TYPE_JSON)))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.True(t, strings.Contains(logs.String(), ErrEncodingFailed))
+	})
+}
@@ -0,0 +1,22 @@
+package response
+
+// Envelope wraps body, error and meta of response into the value which is encoded to JSON.
+// err is nil if there is no error.
+type Envelope func(body any, err any, meta map[string]any) any
+
+// Envelope with "error", "body" and "meta" fields of DefaultResponse
+func DefaultEnvelope(body any, err any, meta map[string]any) any {
+	return DefaultResponse[any, any]{
+		Error: err,
+		Body:  body,
+		Meta:  meta,
+	}
+}
+
+// Envelope without wrapping. Body is sent on success, error is sent on failure and meta is dropped
+func BareEnvelope(body any, err any, meta map[string]any) any {
+	if err != nil {
+		return err
+	}
+	return body
+}
@@ -0,0 +1,244 @@
+package response
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/Moranilt/http-utils/tiny_errors"
+	go_json "github.com/goccy/go-json"
+)
+
+const (
+	ERR_CODE_UnknownFields = 989
+)
+
+const (
+	ErrUnknownFields = "unknown fields: %s"
+)
+
+const (
+	QueryFields = "fields"
+)
+
+var jsonMarshaler = reflect.TypeFor[json.Marshaler]()
+
+// Tree of fields which are sent to the client. nil value means the whole field
+type FieldSet map[string]FieldSet
+
+// Parse comma separated list of fields. Nested fields are separated by dot, e.g. "id,name,owner.email"
+func ParseFields(list string) FieldSet {
+	var fields FieldSet
+	for _, path := range strings.Split(list, ",") {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+		if fields == nil {
+			fields = make(FieldSet)
+		}
+		fields.add(strings.Split(path, "."))
+	}
+	return fields
+}
+
+func (f FieldSet) add(path []string) {
+	name := path[0]
+	children, exists := f[name]
+	if len(path) == 1 {
+		f[name] = nil
+		return
+	}
+	if exists && children == nil {
+		return
+	}
+	if children == nil {
+		children = make(FieldSet)
+		f[name] = children
+	}
+	children.add(path[1:])
+}
+
+// Paths of fields which are not found in type t by json tags. Fields of maps and interfaces are not checked
+func (f FieldSet) Unknown(t reflect.Type) []string {
+	var unknown []string
+	f.unknown(t, "", &unknown)
+	slices.Sort(unknown)
+	return unknown
+}
+
+func (f FieldSet) unknown(t reflect.Type, prefix string, unknown *[]string) {
+	for t != nil && (t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() == reflect.Interface {
+		return
+	}
+
+	for name, children := range f {
+		var fieldType reflect.Type
+		switch {
+		case t.Kind() == reflect.Map && t.Key().Kind() == reflect.String:
+			fieldType = t.Elem()
+		case t.Kind() == reflect.Struct && !t.Implements(jsonMarshaler) && !reflect.PointerTo(t).Implements(jsonMarshaler):
+			field, ok := jsonField(t, name)
+			if !ok {
+				*unknown = append(*unknown, prefix+name)
+				continue
+			}
+			fieldType = field.Type
+		default:
+			*unknown = append(*unknown, prefix+name)
+			continue
+		}
+		if children != nil {
+			children.unknown(fieldType, prefix+name+".", unknown)
+		}
+	}
+}
+
+// Field of struct encoded with name, including fields of embedded structs
+func jsonField(t reflect.Type, name string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag, hasTag := field.Tag.Lookup("json")
+		tagName, _, _ := strings.Cut(tag, ",")
+		if tagName == "-" && tag == "-" {
+			continue
+		}
+
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+		if field.Anonymous && (!hasTag || tagName == "") && fieldType.Kind() == reflect.Struct {
+			if embedded, ok := jsonField(fieldType, name); ok {
+				return embedded, true
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if tagName == "" {
+			tagName = field.Name
+		}
+		if tagName == name {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// Parse "fields" query param of request and check it against type of response body.
+// Returns nil FieldSet if param is empty and error with status 400 if there are unknown fields.
+func FieldsFromRequest(r *http.Request, bodyType reflect.Type) (FieldSet, tiny_errors.ErrorHandler) {
+	fields := ParseFields(r.URL.Query().Get(QueryFields))
+	if fields == nil {
+		return nil, nil
+	}
+	if unknown := fields.Unknown(bodyType); len(unknown) > 0 {
+		return nil, tiny_errors.New(
+			ERR_CODE_UnknownFields,
+			tiny_errors.Message(ErrUnknownFields, strings.Join(unknown, ", ")),
+			tiny_errors.Detail(QueryFields, strings.Join(unknown, ",")),
+		)
+	}
+	return fields, nil
+}
+
+// Send only fields of body from the set. Error and meta are not filtered
+func WithFields(fields FieldSet) Option {
+	return func(c *config) {
+		c.fields = fields
+	}
+}
+
+// Filter body by fields of config. Fields are kept in order of encoded body
+func (c *config) project(body any) (any, error) {
+	if c.fields == nil || isNil(body) {
+		return body, nil
+	}
+	data, err := go_json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	filtered, err := c.fields.filterJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	return json.RawMessage(filtered), nil
+}
+
+// Keep only members of objects from the set. Fields of arrays are applied to every item,
+// other values are returned as is
+func (f FieldSet) filterJSON(data []byte) ([]byte, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return data, nil
+	}
+
+	var b bytes.Buffer
+	switch data[0] {
+	case '{':
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		if _, err := decoder.Token(); err != nil {
+			return nil, err
+		}
+		b.WriteByte('{')
+		for decoder.More() {
+			token, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			var value json.RawMessage
+			if err := decoder.Decode(&value); err != nil {
+				return nil, err
+			}
+			name, _ := token.(string)
+			children, ok := f[name]
+			if !ok {
+				continue
+			}
+			if children != nil {
+				if value, err = children.filterJSON(value); err != nil {
+					return nil, err
+				}
+			}
+			key, err := json.Marshal(name)
+			if err != nil {
+				return nil, err
+			}
+			if b.Len() > 1 {
+				b.WriteByte(',')
+			}
+			b.Write(key)
+			b.WriteByte(':')
+			b.Write(value)
+		}
+		b.WriteByte('}')
+	case '[':
+		var items []json.RawMessage
+		if err := json.Unmarshal(data, &items); err != nil {
+			return nil, err
+		}
+		b.WriteByte('[')
+		for i, item := range items {
+			filtered, err := f.filterJSON(item)
+			if err != nil {
+				return nil, err
+			}
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			b.Write(filtered)
+		}
+		b.WriteByte(']')
+	default:
+		return data, nil
+	}
+	return b.Bytes(), nil
+}
@@ -0,0 +1,138 @@
+package response
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Moranilt/http-utils/tiny_errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type fieldsOwner struct {
+	Email string `json:"email"`
+	Phone string `json:"phone"`
+}
+
+type fieldsBase struct {
+	Id int `json:"id"`
+}
+
+type fieldsItem struct {
+	fieldsBase
+	Name      string            `json:"name"`
+	Owner     *fieldsOwner      `json:"owner"`
+	Members   []fieldsOwner     `json:"members"`
+	Labels    map[string]string `json:"labels"`
+	Extra     any               `json:"extra"`
+	CreatedAt time.Time         `json:"created_at"`
+	Secret    string            `json:"-"`
+}
+
+func TestParseFields(t *testing.T) {
+	assert.Nil(t, ParseFields(""))
+	assert.Nil(t, ParseFields(" , "))
+	assert.Equal(t, FieldSet{
+		"id":      nil,
+		"owner":   FieldSet{"email": nil},
+		"members": nil,
+	}, ParseFields("id, owner.email,members.phone,members"))
+}
+
+func TestFieldSetUnknown(t *testing.T) {
+	itemType := reflect.TypeFor[[]*fieldsItem]()
+
+	assert.Empty(t, ParseFields("id,name,owner.email,members.phone,labels.any,extra.any.thing,created_at").Unknown(itemType))
+	assert.Equal(t, []string{"Secret", "created_at.year", "owner.address", "unknown"},
+		ParseFields("unknown,owner.address,created_at.year,Secret").Unknown(itemType))
+	assert.Equal(t, []string{"id"}, ParseFields("id").Unknown(reflect.TypeFor[string]()))
+	assert.Empty(t, ParseFields("id").Unknown(reflect.TypeFor[any]()))
+}
+
+func TestFieldsFromRequest(t *testing.T) {
+	itemType := reflect.TypeFor[fieldsItem]()
+
+	fields, err := FieldsFromRequest(httptest.NewRequest(http.MethodGet, "/", nil), itemType)
+	assert.Nil(t, err)
+	assert.Nil(t, fields)
+
+	fields, err = FieldsFromRequest(httptest.NewRequest(http.MethodGet, "/?fields=id,owner.email", nil), itemType)
+	assert.Nil(t, err)
+	assert.Equal(t, FieldSet{"id": nil, "owner": FieldSet{"email": nil}}, fields)
+
+	fields, err = FieldsFromRequest(httptest.NewRequest(http.MethodGet, "/?fields=id,age,owner.age", nil), itemType)
+	assert.Nil(t, fields)
+	if assert.NotNil(t, err) {
+		assert.Equal(t, ERR_CODE_UnknownFields, err.GetCode())
+		assert.Equal(t, http.StatusBadRequest, err.GetHTTPStatus())
+		assert.Equal(t, "unknown fields: age, owner.age", err.GetMessage())
+	}
+}
+
+func TestWithFields(t *testing.T) {
+	items := []fieldsItem{
+		{
+			fieldsBase: fieldsBase{Id: 1},
+			Name:       "first",
+			Owner:      &fieldsOwner{Email: "a@example.com", Phone: "1"},
+			Members:    []fieldsOwner{{Email: "b@example.com", Phone: "2"}},
+		},
+		{fieldsBase: fieldsBase{Id: 2}, Name: "second"},
+	}
+	fields := ParseFields("id,owner.email,members.phone")
+
+	rec := httptest.NewRecorder()
+	SuccessResponse(rec, items, http.StatusOK, WithFields(fields), WithMeta("total", 2))
+	assert.JSONEq(t, `{
+		"error": null,
+		"body": [
+			{"id": 1, "owner": {"email": "a@example.com"}, "members": [{"phone": "2"}]},
+			{"id": 2, "owner": null, "members": null}
+		],
+		"meta": {"total": 2}
+	}`, rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	ErrorResponse(rec, tiny_errors.New(1), http.StatusBadRequest, WithFields(fields))
+	assert.JSONEq(t, `{"error":{"code":1,"message":"","details":null},"body":null}`, rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	err := NDJSON(rec, http.StatusOK, FromSlice(items), WithFields(ParseFields("name")))
+	assert.NoError(t, err)
+	assert.Equal(t, "{\"name\":\"first\"}\n{\"name\":\"second\"}\n", rec.Body.String())
+}
+
+func TestWithFieldsOrder(t *testing.T) {
+	item := fieldsItem{
+		fieldsBase: fieldsBase{Id: 1},
+		Name:       "first",
+		Owner:      &fieldsOwner{Email: "a@example.com", Phone: "1"},
+	}
+
+	rec := httptest.NewRecorder()
+	SuccessResponse(rec, item, http.StatusOK, WithFields(ParseFields("owner.phone,owner.email,name,id")))
+	assert.Equal(t, `{"error":null,"body":{"id":1,"name":"first","owner":{"email":"a@example.com","phone":"1"}}}`+"\n", rec.Body.String())
+}
+
+func TestWithFieldsCSV(t *testing.T) {
+	items := []fieldsItem{
+		{
+			fieldsBase: fieldsBase{Id: 1},
+			Name:       "first",
+			Owner:      &fieldsOwner{Email: "a@example.com", Phone: "1"},
+		},
+		{fieldsBase: fieldsBase{Id: 2}, Name: "second"},
+	}
+
+	rec := httptest.NewRecorder()
+	err := CSV(rec, http.StatusOK, items, WithFields(ParseFields("owner.email,id")))
+	assert.NoError(t, err)
+	assert.Equal(t, "id,owner\n1,\"{\"\"email\"\":\"\"a@example.com\"\"}\"\n2,\n", rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	err = CSVStream(rec, http.StatusOK, FromSlice(items), WithFields(ParseFields("name")))
+	assert.NoError(t, err)
+	assert.Equal(t, "name\nfirst\nsecond\n", rec.Body.String())
+}
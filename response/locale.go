@@ -0,0 +1,93 @@
+package response
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Moranilt/http-utils/tiny_errors"
+)
+
+// Locales of Accept-Language header ordered by quality. Locales with zero quality and "*" are skipped
+//
+// Example:
+//
+//	ParseAcceptLanguage("de-CH, fr;q=0.7, en;q=0.9") // [de-CH en fr]
+func ParseAcceptLanguage(header string) []string {
+	type weighted struct {
+		locale  string
+		quality float64
+	}
+
+	var items []weighted
+	for _, part := range strings.Split(header, ",") {
+		locale, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		locale = strings.TrimSpace(locale)
+		if locale == "" || locale == "*" {
+			continue
+		}
+		quality := 1.0
+		for _, param := range strings.Split(params, ";") {
+			name, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if strings.TrimSpace(name) != "q" {
+				continue
+			}
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err == nil {
+				quality = parsed
+			}
+		}
+		if quality <= 0 {
+			continue
+		}
+		items = append(items, weighted{locale: locale, quality: quality})
+	}
+
+	slices.SortStableFunc(items, func(a, b weighted) int {
+		return cmp.Compare(b.quality, a.quality)
+	})
+	locales := make([]string, len(items))
+	for i, item := range items {
+		locales[i] = item.locale
+	}
+	return locales
+}
+
+// Set locale of error messages. It has priority over locale of context and Accept-Language header
+func WithLocale(locale string) Option {
+	return func(c *config) {
+		c.locale = locale
+	}
+}
+
+// Locales of error messages in order of priority: locale of option, locale of request context
+// and locales of Accept-Language header
+func (c *config) locales() []string {
+	var locales []string
+	if c.locale != "" {
+		locales = append(locales, c.locale)
+	}
+	if c.request != nil {
+		if locale := tiny_errors.LocaleFromContext(c.request.Context()); locale != "" {
+			locales = append(locales, locale)
+		}
+		locales = append(locales, ParseAcceptLanguage(c.request.Header.Get("Accept-Language"))...)
+	}
+	return locales
+}
+
+// Translate message of error to locales of response
+func localizeError[Err any](responseErr Err, cfg *config) Err {
+	if isNil(responseErr) {
+		return responseErr
+	}
+	handler, ok := any(responseErr).(tiny_errors.ErrorHandler)
+	if !ok {
+		return responseErr
+	}
+	if localized, ok := tiny_errors.Localize(handler, cfg.locales()...).(Err); ok {
+		return localized
+	}
+	return responseErr
+}
@@ -0,0 +1,58 @@
+package response
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Moranilt/http-utils/tiny_errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAcceptLanguage(t *testing.T) {
+	assert.Empty(t, ParseAcceptLanguage(""))
+	assert.Equal(t, []string{"de-CH", "en", "fr"}, ParseAcceptLanguage("de-CH, fr;q=0.7, en;q=0.9, *;q=0.5, ru;q=0"))
+}
+
+func TestLocalizedErrors(t *testing.T) {
+	tiny_errors.RegisterLocale("en", map[int]string{1: "User %s not found"})
+	tiny_errors.RegisterLocale("de", map[int]string{1: "Benutzer %s nicht gefunden"})
+	tiny_errors.RegisterLocale("fr", map[int]string{1: "Utilisateur %s introuvable"})
+	tiny_errors.SetDefaultLocale("en")
+	t.Cleanup(tiny_errors.ResetLocales)
+
+	err := tiny_errors.New(1, tiny_errors.MessageArgs("John"))
+
+	t.Run("accept language", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Accept-Language", "es, de;q=0.8")
+		rec := httptest.NewRecorder()
+		ErrorResponse(rec, err, http.StatusNotFound, Request(req))
+
+		assert.Equal(t, "Benutzer John nicht gefunden", decodeMap(t, rec)["error"].(map[string]any)["message"])
+	})
+
+	t.Run("context locale", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Accept-Language", "de")
+		req = req.WithContext(tiny_errors.ContextWithLocale(req.Context(), "fr"))
+		rec := httptest.NewRecorder()
+		ProblemResponse(rec, err, http.StatusNotFound, Request(req))
+
+		assert.Equal(t, "Utilisateur John introuvable", decodeMap(t, rec)["detail"])
+	})
+
+	t.Run("locale option", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		Default(rec, "", err, http.StatusNotFound, WithLocale("en"))
+
+		assert.Equal(t, "User John not found", decodeMap(t, rec)["error"].(map[string]any)["message"])
+	})
+
+	t.Run("no locale", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		Default(rec, "", err, http.StatusNotFound)
+
+		assert.Equal(t, "User John not found", decodeMap(t, rec)["error"].(map[string]any)["message"])
+	})
+}
@@ -0,0 +1,60 @@
+package response
+
+import (
+	"context"
+	"maps"
+	"sync"
+)
+
+type metaKey struct{}
+
+type meta struct {
+	values map[string]any
+	mu     sync.Mutex
+}
+
+// Add container of meta to context. If context already has a container, it is reused,
+// so meta added by middleware is kept.
+//
+// Example:
+//
+//	func Timing(next http.Handler) http.Handler {
+//		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+//			ctx := response.ContextWithMeta(r.Context())
+//			response.AddMeta(ctx, "region", "eu-west-1")
+//			next.ServeHTTP(w, r.WithContext(ctx))
+//		})
+//	}
+func ContextWithMeta(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(metaKey{}).(*meta); ok {
+		return ctx
+	}
+	return context.WithValue(ctx, metaKey{}, &meta{values: make(map[string]any)})
+}
+
+// Add value to meta section of response. Returns false if context has no container
+// of meta created by ContextWithMeta. handler.HandlerMaker creates it for every request.
+func AddMeta(ctx context.Context, key string, value any) bool {
+	m, ok := ctx.Value(metaKey{}).(*meta)
+	if !ok {
+		return false
+	}
+	m.mu.Lock()
+	m.values[key] = value
+	m.mu.Unlock()
+	return true
+}
+
+// Get copy of meta stored in context. Returns nil if meta is empty
+func MetaFromContext(ctx context.Context) map[string]any {
+	m, ok := ctx.Value(metaKey{}).(*meta)
+	if !ok {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.values) == 0 {
+		return nil
+	}
+	return maps.Clone(m.values)
+}
@@ -0,0 +1,197 @@
+package response
+
+import (
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/Moranilt/http-utils/logger"
+)
+
+type ErrorFormat string
+
+const (
+	// Errors are sent in DefaultResponse envelope. Client can ask for problem details
+	// by Accept header "application/problem+json"
+	FormatDefault ErrorFormat = "default"
+
+	// Errors are always sent as problem details (RFC 9457)
+	FormatProblem ErrorFormat = "problem"
+)
+
+type config struct {
+	request     *http.Request
+	errorFormat ErrorFormat
+	envelope    Envelope
+	meta        map[string]any
+	contentType string
+	charset     string
+	indent      string
+	prettyQuery bool
+	escapeHTML  bool
+	buffered    bool
+	logger      logger.Logger
+
+	flushEvery    int
+	flushInterval time.Duration
+
+	csv    csvConfig
+	fields FieldSet
+	locale string
+}
+
+// Option is a function type that can be used to configure a response.
+type Option func(*config)
+
+var defaultOptions atomic.Value
+
+func init() {
+	defaultOptions.Store([]Option{})
+}
+
+// Set options which are applied to every response before options of the call.
+// Should be called on start of the service.
+func SetDefaults(options ...Option) {
+	defaultOptions.Store(options)
+}
+
+func newConfig(options []Option) *config {
+	cfg := &config{
+		errorFormat: FormatDefault,
+		contentType: ContentTypeJSON,
+		charset:     defaultCharset,
+		prettyQuery: true,
+		escapeHTML:  true,
+		buffered:    true,
+		logger:      logger.Default(),
+
+		flushEvery:    defaultFlushEvery,
+		flushInterval: defaultFlushInterval,
+
+		csv: defaultCSVConfig(),
+	}
+	for _, opt := range defaultOptions.Load().([]Option) {
+		opt(cfg)
+	}
+	for _, opt := range options {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// Request which is responded. Used to negotiate format of response by headers
+// and to fill request related fields
+func Request(r *http.Request) Option {
+	return func(c *config) {
+		c.request = r
+	}
+}
+
+// Set format of errors
+func WithErrorFormat(format ErrorFormat) Option {
+	return func(c *config) {
+		c.errorFormat = format
+	}
+}
+
+// Set envelope of responses. Default is DefaultEnvelope
+//
+// Example:
+//
+//	type MyResponse struct {
+//		Data   any            `json:"data"`
+//		Errors []any          `json:"errors,omitempty"`
+//		Meta   map[string]any `json:"meta,omitempty"`
+//	}
+//
+//	response.SetDefaults(response.WithEnvelope(func(body any, err any, meta map[string]any) any {
+//		resp := MyResponse{Data: body, Meta: meta}
+//		if err != nil {
+//			resp.Errors = []any{err}
+//		}
+//		return resp
+//	}))
+func WithEnvelope(envelope Envelope) Option {
+	return func(c *config) {
+		c.envelope = envelope
+	}
+}
+
+// Add value to meta section of response
+func WithMeta(key string, value any) Option {
+	return func(c *config) {
+		if c.meta == nil {
+			c.meta = make(map[string]any)
+		}
+		c.meta[key] = value
+	}
+}
+
+// Set media type of JSON responses. Default is "application/json"
+func ContentType(mediaType string) Option {
+	return func(c *config) {
+		c.contentType = mediaType
+	}
+}
+
+// Set charset of Content-Type header. Default is "utf-8". Empty charset is not sent
+func Charset(charset string) Option {
+	return func(c *config) {
+		c.charset = charset
+	}
+}
+
+// Always indent JSON with indent
+func Indent(indent string) Option {
+	return func(c *config) {
+		c.indent = indent
+	}
+}
+
+// Indent JSON when request has query param "pretty", e.g. "?pretty" or "?pretty=true".
+// Enabled by default, works only with Request option
+func PrettyQuery(enabled bool) Option {
+	return func(c *config) {
+		c.prettyQuery = enabled
+	}
+}
+
+// Escape <, > and & in JSON strings. Enabled by default
+func EscapeHTML(enabled bool) Option {
+	return func(c *config) {
+		c.escapeHTML = enabled
+	}
+}
+
+// Encode response to buffer before sending status. If encoding fails, error with status 500 is sent.
+// Enabled by default. Disable it to write large responses without holding them in memory
+func Buffered(enabled bool) Option {
+	return func(c *config) {
+		c.buffered = enabled
+	}
+}
+
+// Set logger to report encoding errors. Default is logger.Default()
+func WithLogger(log logger.Logger) Option {
+	return func(c *config) {
+		c.logger = log
+	}
+}
+
+// Meta of response: meta of request context merged with meta of options
+func (c *config) responseMeta() map[string]any {
+	var result map[string]any
+	if c.request != nil {
+		result = MetaFromContext(c.request.Context())
+	}
+	if len(c.meta) == 0 {
+		return result
+	}
+	if result == nil {
+		result = make(map[string]any, len(c.meta))
+	}
+	for key, value := range c.meta {
+		result[key] = value
+	}
+	return result
+}
@@ -0,0 +1,224 @@
+package response
+
+import (
+	"mime"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/Moranilt/http-utils/tiny_errors"
+	go_json "github.com/goccy/go-json"
+)
+
+const (
+	ContentTypeProblemJSON = "application/problem+json"
+	problemTypeBlank       = "about:blank"
+)
+
+// Problem details of RFC 9457
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+
+	// Extension members. Members with names of standard fields are ignored
+	Extensions map[string]any `json:"-"`
+}
+
+var problemMembers = map[string]struct{}{
+	"type":     {},
+	"title":    {},
+	"status":   {},
+	"detail":   {},
+	"instance": {},
+}
+
+func (p Problem) MarshalJSON() ([]byte, error) {
+	members := make(map[string]any, len(p.Extensions)+5)
+	for name, value := range p.Extensions {
+		if _, reserved := problemMembers[name]; !reserved {
+			members[name] = value
+		}
+	}
+	members["type"] = p.Type
+	members["title"] = p.Title
+	members["status"] = p.Status
+	if p.Detail != "" {
+		members["detail"] = p.Detail
+	}
+	if p.Instance != "" {
+		members["instance"] = p.Instance
+	}
+	return go_json.Marshal(members)
+}
+
+// Decode standard members of problem and keep other members in Extensions
+func (p *Problem) UnmarshalJSON(data []byte) error {
+	type standard Problem
+	var decoded standard
+	if err := go_json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	var members map[string]any
+	if err := go_json.Unmarshal(data, &members); err != nil {
+		return err
+	}
+
+	*p = Problem(decoded)
+	p.Extensions = nil
+	for name, value := range members {
+		if _, reserved := problemMembers[name]; reserved {
+			continue
+		}
+		if p.Extensions == nil {
+			p.Extensions = make(map[string]any)
+		}
+		p.Extensions[name] = value
+	}
+	return nil
+}
+
+// Type of problem registered for error code
+type ProblemType struct {
+	// URI which identifies the problem type
+	URI string
+
+	// Short summary of the problem type. If empty, status text of HTTP status is used
+	Title string
+}
+
+var problemTypes = struct {
+	types   map[int]ProblemType
+	baseURI string
+	mu      sync.RWMutex
+}{
+	types: make(map[int]ProblemType),
+}
+
+// Register type of problem for error code
+//
+// Example:
+//
+//	response.RegisterProblemType(ErrCodeUserNotFound, response.ProblemType{
+//		URI:   "https://errors.example.com/user-not-found",
+//		Title: "User not found",
+//	})
+func RegisterProblemType(code int, problemType ProblemType) {
+	problemTypes.mu.Lock()
+	problemTypes.types[code] = problemType
+	problemTypes.mu.Unlock()
+}
+
+// Set base URI of problem types for codes which are not registered.
+// Type of problem will be base URI with error code appended, e.g. "https://errors.example.com/1001"
+func SetProblemTypeBaseURI(uri string) {
+	problemTypes.mu.Lock()
+	problemTypes.baseURI = uri
+	problemTypes.mu.Unlock()
+}
+
+// Get registered type of problem for error code
+func GetProblemType(code int) (ProblemType, bool) {
+	problemTypes.mu.RLock()
+	defer problemTypes.mu.RUnlock()
+
+	if problemType, ok := problemTypes.types[code]; ok {
+		return problemType, true
+	}
+	if problemTypes.baseURI != "" {
+		return ProblemType{URI: problemTypes.baseURI + strconv.Itoa(code)}, true
+	}
+	return ProblemType{}, false
+}
+
+// Convert error to problem details.
+//
+// Code of tiny_errors.ErrorHandler is used to find problem type, message becomes detail
+// and details become extension members. Other errors have only detail.
+func NewProblem(responseErr any, status int, r *http.Request) *Problem {
+	problem := &Problem{
+		Type:       problemTypeBlank,
+		Status:     status,
+		Extensions: make(map[string]any),
+	}
+
+	switch err := responseErr.(type) {
+	case tiny_errors.ErrorHandler:
+		if problem.Status == 0 {
+			problem.Status = err.GetHTTPStatus()
+		}
+		problem.Detail = err.GetMessage()
+		problem.Extensions["code"] = err.GetCode()
+		for name, value := range err.GetDetails() {
+			problem.Extensions[name] = value
+		}
+		if problemType, ok := GetProblemType(err.GetCode()); ok {
+			problem.Type = problemType.URI
+			problem.Title = problemType.Title
+		}
+	case error:
+		problem.Detail = err.Error()
+	}
+
+	if problem.Title == "" {
+		problem.Title = http.StatusText(problem.Status)
+	}
+	if r != nil {
+		problem.Instance = r.URL.Path
+	}
+	return problem
+}
+
+// Problem details should be sent to the client
+func (c *config) useProblem() bool {
+	if c.errorFormat == FormatProblem {
+		return true
+	}
+	if c.request == nil {
+		return false
+	}
+	return acceptsMediaType(c.request, ContentTypeProblemJSON)
+}
+
+// Accept header of request contains media type
+func acceptsMediaType(r *http.Request, mediaType string) bool {
+	return mediaTypeQuality(r, mediaType) > 0
+}
+
+// Quality of media type in Accept header of request. Returns 0 if media type is not listed
+func mediaTypeQuality(r *http.Request, mediaType string) float64 {
+	var quality float64
+	for _, accept := range r.Header.Values("Accept") {
+		for _, part := range strings.Split(accept, ",") {
+			parsed, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+			if err != nil || parsed != mediaType {
+				continue
+			}
+			value := 1.0
+			if q, ok := params["q"]; ok {
+				if value, err = strconv.ParseFloat(q, 64); err != nil {
+					continue
+				}
+			}
+			quality = max(quality, value)
+		}
+	}
+	return quality
+}
+
+// Value is nil or nil pointer, map, slice or interface
+func isNil(value any) bool {
+	if value == nil {
+		return true
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Interface, reflect.Func, reflect.Chan:
+		return v.IsNil()
+	}
+	return false
+}
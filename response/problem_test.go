@@ -0,0 +1,192 @@
+package response
+
+import (
+	"encoding/json"
+	"errors"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Moranilt/http-utils/tiny_errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func decodeMap(t *testing.T, rec *httptest.ResponseRecorder) map[string]any {
+	var result map[string]any
+	if !assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &result)) {
+		t.FailNow()
+	}
+	return result
+}
+
+func mediaType(rec *httptest.ResponseRecorder) string {
+	mediaType, _, _ := mime.ParseMediaType(rec.Header().Get("Content-Type"))
+	return mediaType
+}
+
+func TestNewProblem(t *testing.T) {
+	t.Cleanup(func() {
+		problemTypes.types = make(map[int]ProblemType)
+		problemTypes.baseURI = ""
+	})
+
+	err := tiny_errors.New(1001, tiny_errors.Message("user not found"), tiny_errors.Detail("user_id", "10"), tiny_errors.HTTPStatus(http.StatusNotFound))
+	req := httptest.NewRequest(http.MethodGet, "/users/10?fields=id", nil)
+
+	t.Run("not registered", func(t *testing.T) {
+		problem := NewProblem(err, 0, req)
+		assert.Equal(t, "about:blank", problem.Type)
+		assert.Equal(t, "Not Found", problem.Title)
+		assert.Equal(t, http.StatusNotFound, problem.Status)
+		assert.Equal(t, "user not found", problem.Detail)
+		assert.Equal(t, "/users/10", problem.Instance)
+		assert.Equal(t, map[string]any{"code": 1001, "user_id": "10"}, problem.Extensions)
+	})
+
+	t.Run("base URI", func(t *testing.T) {
+		SetProblemTypeBaseURI("https://errors.example.com/")
+		problem := NewProblem(err, http.StatusNotFound, nil)
+		assert.Equal(t, "https://errors.example.com/1001", problem.Type)
+		assert.Equal(t, "Not Found", problem.Title)
+		assert.Empty(t, problem.Instance)
+	})
+
+	t.Run("registered", func(t *testing.T) {
+		RegisterProblemType(1001, ProblemType{URI: "https://errors.example.com/user-not-found", Title: "User not found"})
+		problem := NewProblem(err, http.StatusNotFound, req)
+		assert.Equal(t, "https://errors.example.com/user-not-found", problem.Type)
+		assert.Equal(t, "User not found", problem.Title)
+	})
+
+	t.Run("plain error", func(t *testing.T) {
+		problem := NewProblem(errors.New("internal"), http.StatusInternalServerError, nil)
+		assert.Equal(t, "about:blank", problem.Type)
+		assert.Equal(t, "Internal Server Error", problem.Title)
+		assert.Equal(t, "internal", problem.Detail)
+		assert.Empty(t, problem.Extensions)
+	})
+}
+
+func TestProblemMarshalJSON(t *testing.T) {
+	problem := Problem{
+		Type:   "about:blank",
+		Title:  "Bad Request",
+		Status: http.StatusBadRequest,
+		Extensions: map[string]any{
+			"code":   1,
+			"status": "ignored",
+		},
+	}
+
+	data, err := json.Marshal(problem)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"type":"about:blank","title":"Bad Request","status":400,"code":1}`, string(data))
+}
+
+func TestProblemUnmarshalJSON(t *testing.T) {
+	var problem Problem
+	err := json.Unmarshal([]byte(`{"type":"about:blank","title":"Not Found","status":404,"detail":"user not found","code":1001,"user_id":"1"}`), &problem)
+	assert.NoError(t, err)
+	assert.Equal(t, Problem{
+		Type:       "about:blank",
+		Title:      "Not Found",
+		Status:     http.StatusNotFound,
+		Detail:     "user not found",
+		Extensions: map[string]any{"code": float64(1001), "user_id": "1"},
+	}, problem)
+}
+
+func TestErrorResponseFormat(t *testing.T) {
+	err := tiny_errors.New(1, tiny_errors.Message("bad request"))
+
+	t.Run("default envelope", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		ErrorResponse(rec, err, http.StatusBadRequest, Request(httptest.NewRequest(http.MethodGet, "/", nil)))
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.NotEqual(t, ContentTypeProblemJSON, mediaType(rec))
+		body := decodeMap(t, rec)
+		assert.Contains(t, body, "error")
+		assert.Contains(t, body, "body")
+	})
+
+	t.Run("accept header", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/users", nil)
+		req.Header.Set("Accept", "application/json, application/problem+json;q=0.9")
+		rec := httptest.NewRecorder()
+		ErrorResponse(rec, err, http.StatusBadRequest, Request(req))
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, ContentTypeProblemJSON, mediaType(rec))
+		body := decodeMap(t, rec)
+		assert.Equal(t, "bad request", body["detail"])
+		assert.Equal(t, "/users", body["instance"])
+		assert.EqualValues(t, 1, body["code"])
+	})
+
+	t.Run("accept header with zero quality", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Accept", "application/problem+json;q=0")
+		rec := httptest.NewRecorder()
+		ErrorResponse(rec, err, http.StatusBadRequest, Request(req))
+
+		assert.NotEqual(t, ContentTypeProblemJSON, mediaType(rec))
+	})
+
+	t.Run("global format", func(t *testing.T) {
+		SetDefaults(WithErrorFormat(FormatProblem))
+		t.Cleanup(func() { SetDefaults() })
+
+		rec := httptest.NewRecorder()
+		Default(rec, "body", err, http.StatusConflict)
+
+		assert.Equal(t, http.StatusConflict, rec.Code)
+		assert.Equal(t, ContentTypeProblemJSON, mediaType(rec))
+		body := decodeMap(t, rec)
+		assert.Equal(t, "Conflict", body["title"])
+		assert.NotContains(t, body, "body")
+	})
+
+	t.Run("nil error is not a problem", func(t *testing.T) {
+		SetDefaults(WithErrorFormat(FormatProblem))
+		t.Cleanup(func() { SetDefaults() })
+
+		rec := httptest.NewRecorder()
+		var nilErr *tiny_errors.Error
+		Default(rec, "body", nilErr, http.StatusOK)
+
+		assert.NotEqual(t, ContentTypeProblemJSON, mediaType(rec))
+		assert.Equal(t, "body", decodeMap(t, rec)["body"])
+	})
+}
+
+func TestFieldErrorsFormat(t *testing.T) {
+	tiny_errors.RegisterRuleMessages("de", map[string]string{"required": "ist erforderlich"})
+	t.Cleanup(tiny_errors.ResetLocales)
+
+	errs := tiny_errors.NewFieldErrors().Add("name", "required", "is required", nil)
+
+	t.Run("envelope", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/users", nil)
+		req.Header.Set("Accept-Language", "de")
+		rec := httptest.NewRecorder()
+		ErrorResponse(rec, errs.Err(), errs.GetHTTPStatus(), Request(req))
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.JSONEq(t, `{"error":{"code":980,"message":"validation failed","details":{"fields":[
+			{"field":"name","rule":"required","message":"ist erforderlich"}
+		]}},"body":null}`, rec.Body.String())
+	})
+
+	t.Run("problem", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/users", nil)
+		req.Header.Set("Accept", ContentTypeProblemJSON)
+		rec := httptest.NewRecorder()
+		ErrorResponse(rec, errs.Err(), errs.GetHTTPStatus(), Request(req))
+
+		assert.Equal(t, ContentTypeProblemJSON, mediaType(rec))
+		assert.JSONEq(t, `{"type":"about:blank","title":"Bad Request","status":400,"detail":"validation failed",
+			"instance":"/users","code":980,"fields":[{"field":"name","rule":"required","message":"is required"}]}`, rec.Body.String())
+	})
+}
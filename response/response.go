@@ -2,35 +2,87 @@ package response
 
 import (
 	"net/http"
-
-	go_json "github.com/goccy/go-json"
 )
 
 type DefaultResponse[T any, E any] struct {
-	Error E `json:"error"`
-	Body  T `json:"body"`
+	Error E              `json:"error"`
+	Body  T              `json:"body"`
+	Meta  map[string]any `json:"meta,omitempty"`
 }
 
-func Default[Body any, Err any](w http.ResponseWriter, body Body, responseErr Err, status int) {
-	w.WriteHeader(status)
-	go_json.NewEncoder(w).Encode(DefaultResponse[Body, Err]{
-		Error: responseErr,
-		Body:  body,
-	})
+func Default[Body any, Err any](w http.ResponseWriter, body Body, responseErr Err, status int, options ...Option) {
+	cfg := newConfig(options)
+	responseErr = localizeError(responseErr, cfg)
+	if !isNil(responseErr) && cfg.useProblem() {
+		writeProblem(w, responseErr, status, cfg)
+		return
+	}
+	writeDefault(w, body, responseErr, status, cfg)
 }
 
-func ErrorResponse[Err any](w http.ResponseWriter, responseErr Err, status int) {
-	w.WriteHeader(status)
-	go_json.NewEncoder(w).Encode(DefaultResponse[any, Err]{
+func ErrorResponse[Err any](w http.ResponseWriter, responseErr Err, status int, options ...Option) {
+	cfg := newConfig(options)
+	responseErr = localizeError(responseErr, cfg)
+	if !isNil(responseErr) && cfg.useProblem() {
+		writeProblem(w, responseErr, status, cfg)
+		return
+	}
+	if cfg.envelope != nil {
+		writeEnvelope(w, nil, responseErr, status, cfg)
+		return
+	}
+	writeJSON(w, status, cfg.contentType, DefaultResponse[any, Err]{
 		Error: responseErr,
 		Body:  nil,
-	})
+		Meta:  cfg.responseMeta(),
+	}, cfg)
+}
+
+func SuccessResponse[Body any](w http.ResponseWriter, body Body, status int, options ...Option) {
+	writeDefault[Body, *string](w, body, nil, status, newConfig(options))
+}
+
+// Send error as problem details (RFC 9457)
+func ProblemResponse(w http.ResponseWriter, responseErr any, status int, options ...Option) {
+	cfg := newConfig(options)
+	writeProblem(w, localizeError(responseErr, cfg), status, cfg)
 }
 
-func SuccessResponse[Body any](w http.ResponseWriter, body Body, status int) {
-	w.WriteHeader(status)
-	go_json.NewEncoder(w).Encode(DefaultResponse[Body, *string]{
-		Error: nil,
+// Write body filtered by fields in envelope of config
+func writeDefault[Body any, Err any](w http.ResponseWriter, body Body, responseErr Err, status int, cfg *config) {
+	if cfg.fields != nil {
+		projected, err := cfg.project(body)
+		if err != nil {
+			cfg.logger.Error(ErrEncodingFailed, "error", err.Error(), "status", status)
+			writeEncodingError(w, cfg)
+			return
+		}
+		cfg.fields = nil
+		writeDefault(w, projected, responseErr, status, cfg)
+		return
+	}
+	if cfg.envelope != nil {
+		writeEnvelope(w, body, responseErr, status, cfg)
+		return
+	}
+	writeJSON(w, status, cfg.contentType, DefaultResponse[Body, Err]{
+		Error: responseErr,
 		Body:  body,
-	})
+		Meta:  cfg.responseMeta(),
+	}, cfg)
+}
+
+func writeEnvelope(w http.ResponseWriter, body any, responseErr any, status int, cfg *config) {
+	if isNil(responseErr) {
+		responseErr = nil
+	}
+	writeJSON(w, status, cfg.contentType, cfg.envelope(body, responseErr, cfg.responseMeta()), cfg)
+}
+
+func writeProblem(w http.ResponseWriter, responseErr any, status int, cfg *config) {
+	problem := NewProblem(responseErr, status, cfg.request)
+	if meta := cfg.responseMeta(); meta != nil {
+		problem.Extensions["meta"] = meta
+	}
+	writeJSON(w, problem.Status, ContentTypeProblemJSON, problem, cfg)
 }
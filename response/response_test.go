@@ -0,0 +1,78 @@
+package response
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Moranilt/http-utils/tiny_errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMeta(t *testing.T) {
+	assert.False(t, AddMeta(context.Background(), "key", "value"))
+	assert.Nil(t, MetaFromContext(context.Background()))
+
+	ctx := ContextWithMeta(context.Background())
+	assert.Nil(t, MetaFromContext(ctx))
+	assert.True(t, AddMeta(ctx, "page", 1))
+
+	same := ContextWithMeta(ctx)
+	assert.True(t, AddMeta(same, "total", 10))
+	assert.Equal(t, map[string]any{"page": 1, "total": 10}, MetaFromContext(ctx))
+}
+
+func TestEnvelopes(t *testing.T) {
+	err := tiny_errors.New(1, tiny_errors.Message("bad request"))
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	ctx := ContextWithMeta(req.Context())
+	AddMeta(ctx, "request_id", "abc")
+	req = req.WithContext(ctx)
+
+	t.Run("default envelope with meta", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		SuccessResponse(rec, "ok", http.StatusOK, Request(req), WithMeta("page", 2))
+
+		assert.JSONEq(t, `{"error":null,"body":"ok","meta":{"request_id":"abc","page":2}}`, rec.Body.String())
+	})
+
+	t.Run("default envelope without meta", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		SuccessResponse(rec, "ok", http.StatusOK)
+
+		assert.JSONEq(t, `{"error":null,"body":"ok"}`, rec.Body.String())
+	})
+
+	t.Run("bare envelope", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		SuccessResponse(rec, map[string]int{"id": 1}, http.StatusOK, Request(req), WithEnvelope(BareEnvelope))
+		assert.JSONEq(t, `{"id":1}`, rec.Body.String())
+
+		rec = httptest.NewRecorder()
+		ErrorResponse(rec, err, http.StatusBadRequest, WithEnvelope(BareEnvelope))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.JSONEq(t, `{"code":1,"message":"bad request","details":null}`, rec.Body.String())
+	})
+
+	t.Run("custom envelope", func(t *testing.T) {
+		type custom struct {
+			Data    any            `json:"data"`
+			Success bool           `json:"success"`
+			Meta    map[string]any `json:"meta"`
+		}
+		SetDefaults(WithEnvelope(func(body any, err any, meta map[string]any) any {
+			return custom{Data: body, Success: err == nil, Meta: meta}
+		}))
+		t.Cleanup(func() { SetDefaults() })
+
+		rec := httptest.NewRecorder()
+		var nilErr *tiny_errors.Error
+		Default(rec, "ok", nilErr, http.StatusOK, Request(req))
+		assert.JSONEq(t, `{"data":"ok","success":true,"meta":{"request_id":"abc"}}`, rec.Body.String())
+
+		rec = httptest.NewRecorder()
+		ErrorResponse(rec, err, http.StatusBadRequest)
+		assert.JSONEq(t, `{"data":null,"success":false,"meta":null}`, rec.Body.String())
+	})
+}
@@ -0,0 +1,287 @@
+package response
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Moranilt/http-utils/tiny_errors"
+	go_json "github.com/goccy/go-json"
+)
+
+const (
+	ERR_CODE_StreamInterrupted = 990
+)
+
+const (
+	ErrStreamInterrupted = "stream is interrupted"
+)
+
+const (
+	ContentTypeNDJSON = "application/x-ndjson"
+
+	defaultFlushEvery    = 100
+	defaultFlushInterval = time.Second
+)
+
+var newline = []byte("\n")
+
+// PullFunc returns the next item of a stream. ok is false when there are no more items.
+// Returned error interrupts the stream and is reported to the client.
+type PullFunc[T any] func(ctx context.Context) (item T, ok bool, err error)
+
+// Pull items from channel until it is closed
+func FromChannel[T any](ch <-chan T) PullFunc[T] {
+	return func(ctx context.Context) (T, bool, error) {
+		select {
+		case item, ok := <-ch:
+			return item, ok, nil
+		case <-ctx.Done():
+			var empty T
+			return empty, false, ctx.Err()
+		}
+	}
+}
+
+// Pull items from slice
+func FromSlice[T any](items []T) PullFunc[T] {
+	var i int
+	return func(ctx context.Context) (T, bool, error) {
+		if i >= len(items) {
+			var empty T
+			return empty, false, nil
+		}
+		i++
+		return items[i-1], true, nil
+	}
+}
+
+// Flush response after n items. Default is 100
+func FlushEvery(n int) Option {
+	return func(c *config) {
+		c.flushEvery = n
+	}
+}
+
+// Flush response if interval is passed since the last flush. Default is 1 second
+func FlushInterval(interval time.Duration) Option {
+	return func(c *config) {
+		c.flushInterval = interval
+	}
+}
+
+// Write items as newline-delimited JSON, one item per line.
+//
+// If pull returns an error, the last line is an object with "error" field.
+// Streaming stops when the client disconnects. Returns the error which stopped the stream.
+//
+// Example:
+//
+//	rows := make(chan *User)
+//	go db.StreamUsers(r.Context(), rows)
+//	response.NDJSON(w, http.StatusOK, response.FromChannel(rows), response.Request(r))
+func NDJSON[T any](w http.ResponseWriter, status int, pull PullFunc[T], options ...Option) error {
+	s := newStream(w, options)
+	s.writeHeader(ContentTypeNDJSON, status)
+
+	err := runStream(s, pull, func(item T) error {
+		projected, err := s.cfg.project(item)
+		if err != nil {
+			return err
+		}
+		return s.write(projected, nil, newline)
+	})
+	if err != nil && !s.disconnected(err) {
+		s.write(map[string]any{"error": s.streamError(err)}, nil, newline)
+	}
+	s.flush()
+	return err
+}
+
+// Write items as JSON array in "body" field of the response envelope.
+//
+// "error" and "meta" fields are written after the array, so an error returned by pull
+// is reported to the client. Envelope option is not applied to streamed responses.
+// Streaming stops when the client disconnects. Returns the error which stopped the stream.
+//
+// Example:
+//
+//	response.JSONArray(w, http.StatusOK, func(ctx context.Context) (*User, bool, error) {
+//		if !rows.Next() {
+//			return nil, false, rows.Err()
+//		}
+//		var user User
+//		err := rows.StructScan(&user)
+//		return &user, err == nil, err
+//	}, response.Request(r))
+func JSONArray[T any](w http.ResponseWriter, status int, pull PullFunc[T], options ...Option) error {
+	s := newStream(w, options)
+	s.writeHeader(s.cfg.contentType, status)
+
+	if err := s.writeRaw([]byte(`{"body":[`)); err != nil {
+		return err
+	}
+	var count int
+	err := runStream(s, pull, func(item T) error {
+		var prefix []byte
+		if count > 0 {
+			prefix = []byte(",")
+		}
+		count++
+		projected, err := s.cfg.project(item)
+		if err != nil {
+			return err
+		}
+		return s.write(projected, prefix, nil)
+	})
+	if s.disconnected(err) {
+		return err
+	}
+
+	var streamErr any
+	if err != nil {
+		streamErr = s.streamError(err)
+	}
+	s.writeRaw([]byte(`],"error":`))
+	s.write(streamErr, nil, nil)
+	if meta := s.cfg.responseMeta(); meta != nil {
+		s.writeRaw([]byte(`,"meta":`))
+		s.write(meta, nil, nil)
+	}
+	s.writeRaw([]byte("}\n"))
+	s.flush()
+	return err
+}
+
+type stream struct {
+	w          http.ResponseWriter
+	cfg        *config
+	ctx        context.Context
+	controller *http.ResponseController
+	buf        bytes.Buffer
+	pending    int
+	lastFlush  time.Time
+	writeErr   error
+
+	// Called before flushing, e.g. to flush buffer of csv.Writer
+	beforeFlush func()
+}
+
+func newStream(w http.ResponseWriter, options []Option) *stream {
+	cfg := newConfig(options)
+	ctx := context.Background()
+	if cfg.request != nil {
+		ctx = cfg.request.Context()
+	}
+	return &stream{
+		w:          w,
+		cfg:        cfg,
+		ctx:        ctx,
+		controller: http.NewResponseController(w),
+		lastFlush:  time.Now(),
+	}
+}
+
+func (s *stream) writeHeader(mediaType string, status int) {
+	s.w.Header().Set("Content-Type", s.cfg.contentTypeHeader(mediaType))
+	s.w.Header().Set("X-Content-Type-Options", "nosniff")
+	s.w.WriteHeader(status)
+	s.flush()
+}
+
+// Pull items until the end of stream, error or disconnect of the client
+func runStream[T any](s *stream, pull PullFunc[T], write func(item T) error) error {
+	for {
+		if err := s.ctx.Err(); err != nil {
+			return err
+		}
+		item, ok, err := pull(s.ctx)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return nil
+		}
+		if err := write(item); err != nil {
+			return err
+		}
+		s.pending++
+		if s.pending >= s.cfg.flushEvery || time.Since(s.lastFlush) >= s.cfg.flushInterval {
+			if err := s.flush(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Encode value and write it between prefix and suffix
+func (s *stream) write(value any, prefix, suffix []byte) error {
+	s.buf.Reset()
+	s.buf.Write(prefix)
+	encoder := go_json.NewEncoder(&s.buf)
+	encoder.SetEscapeHTML(s.cfg.escapeHTML)
+	if err := encoder.Encode(value); err != nil {
+		s.cfg.logger.Error(ErrEncodingFailed, "error", err.Error())
+		return err
+	}
+	s.buf.Truncate(s.buf.Len() - 1)
+	s.buf.Write(suffix)
+	return s.writeRaw(s.buf.Bytes())
+}
+
+func (s *stream) writeRaw(data []byte) error {
+	if s.writeErr != nil {
+		return s.writeErr
+	}
+	if _, err := s.w.Write(data); err != nil {
+		s.writeErr = err
+		return err
+	}
+	return nil
+}
+
+// Write implements io.Writer
+func (s *stream) Write(data []byte) (int, error) {
+	if err := s.writeRaw(data); err != nil {
+		return 0, err
+	}
+	return len(data), nil
+}
+
+func (s *stream) flush() error {
+	if s.beforeFlush != nil {
+		s.beforeFlush()
+	}
+	s.pending = 0
+	s.lastFlush = time.Now()
+	if s.writeErr != nil {
+		return s.writeErr
+	}
+	err := s.controller.Flush()
+	if err != nil && !errors.Is(err, http.ErrNotSupported) {
+		s.writeErr = err
+		return err
+	}
+	return nil
+}
+
+// Stream is stopped because the client is gone and nothing can be sent
+func (s *stream) disconnected(err error) bool {
+	return err != nil && (s.writeErr != nil || s.ctx.Err() != nil)
+}
+
+// Convert error of stream to error of response and report it to the logger
+func (s *stream) streamError(err error) tiny_errors.ErrorHandler {
+	s.cfg.logger.Error(ErrStreamInterrupted, "error", err.Error())
+	var handler tiny_errors.ErrorHandler
+	if !errors.As(err, &handler) {
+		handler = tiny_errors.New(
+			ERR_CODE_StreamInterrupted,
+			tiny_errors.Message(ErrStreamInterrupted),
+			tiny_errors.HTTPStatus(http.StatusInternalServerError),
+		)
+	}
+	return localizeError(handler, s.cfg)
+}
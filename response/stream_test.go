@@ -0,0 +1,114 @@
+package response
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Moranilt/http-utils/logger"
+	"github.com/Moranilt/http-utils/tiny_errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type streamItem struct {
+	Id   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+var streamItems = []streamItem{{Id: 1, Name: "first"}, {Id: 2, Name: "second"}}
+
+func failingPull(items []streamItem, err error) PullFunc[streamItem] {
+	pull := FromSlice(items)
+	return func(ctx context.Context) (streamItem, bool, error) {
+		item, ok, _ := pull(ctx)
+		if !ok {
+			return item, false, err
+		}
+		return item, true, nil
+	}
+}
+
+func TestNDJSON(t *testing.T) {
+	t.Run("items", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		err := NDJSON(rec, http.StatusOK, FromSlice(streamItems))
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/x-ndjson; charset=utf-8", rec.Header().Get("Content-Type"))
+		assert.Equal(t, "{\"id\":1,\"name\":\"first\"}\n{\"id\":2,\"name\":\"second\"}\n", rec.Body.String())
+		assert.True(t, rec.Flushed)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		err := NDJSON(rec, http.StatusOK, failingPull(streamItems[:1], errors.New("connection reset")), WithLogger(logger.NewMock()))
+
+		assert.Error(t, err)
+		assert.Equal(t, "{\"id\":1,\"name\":\"first\"}\n{\"error\":{\"code\":990,\"message\":\"stream is interrupted\",\"details\":null}}\n", rec.Body.String())
+	})
+
+	t.Run("channel", func(t *testing.T) {
+		ch := make(chan streamItem, len(streamItems))
+		for _, item := range streamItems {
+			ch <- item
+		}
+		close(ch)
+
+		rec := httptest.NewRecorder()
+		err := NDJSON(rec, http.StatusOK, FromChannel(ch), FlushEvery(1))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "{\"id\":1,\"name\":\"first\"}\n{\"id\":2,\"name\":\"second\"}\n", rec.Body.String())
+	})
+
+	t.Run("client disconnected", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		req := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)
+		ch := make(chan streamItem)
+		go func() {
+			ch <- streamItems[0]
+			cancel()
+		}()
+
+		rec := httptest.NewRecorder()
+		err := NDJSON(rec, http.StatusOK, FromChannel(ch), Request(req))
+
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, "{\"id\":1,\"name\":\"first\"}\n", rec.Body.String())
+	})
+}
+
+func TestJSONArray(t *testing.T) {
+	t.Run("items", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		ctx := ContextWithMeta(req.Context())
+		AddMeta(ctx, "total", 2)
+
+		rec := httptest.NewRecorder()
+		err := JSONArray(rec, http.StatusOK, FromSlice(streamItems), Request(req.WithContext(ctx)))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "application/json; charset=utf-8", rec.Header().Get("Content-Type"))
+		assert.JSONEq(t, `{"body":[{"id":1,"name":"first"},{"id":2,"name":"second"}],"error":null,"meta":{"total":2}}`, rec.Body.String())
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		err := JSONArray(rec, http.StatusOK, FromSlice[streamItem](nil))
+
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"body":[],"error":null}`, rec.Body.String())
+	})
+
+	t.Run("error", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		pullErr := tiny_errors.New(5, tiny_errors.Message("database is unavailable"))
+		err := JSONArray(rec, http.StatusOK, failingPull(streamItems, pullErr), WithLogger(logger.NewMock()))
+
+		assert.ErrorIs(t, err, pullErr)
+		assert.JSONEq(t, `{"body":[{"id":1,"name":"first"},{"id":2,"name":"second"}],"error":{"code":5,"message":"database is unavailable","details":null}}`, rec.Body.String())
+	})
+}
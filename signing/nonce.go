@@ -0,0 +1,53 @@
+package signing
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// NonceStore remembers used nonces to reject replayed requests
+type NonceStore interface {
+	// Store nonce for ttl. Returns false if nonce is already stored
+	Add(ctx context.Context, nonce string, ttl time.Duration) (bool, error)
+}
+
+// Interval of removing expired nonces from MemoryNonceStore
+const nonceSweepInterval = time.Minute
+
+// MemoryNonceStore keeps nonces in memory of the current process
+type MemoryNonceStore struct {
+	nonces    map[string]time.Time
+	lastSweep time.Time
+	now       func() time.Time
+	mu        sync.Mutex
+}
+
+// Create new in-memory NonceStore
+func NewMemoryNonceStore() *MemoryNonceStore {
+	return &MemoryNonceStore{
+		nonces: make(map[string]time.Time),
+		now:    time.Now,
+	}
+}
+
+func (m *MemoryNonceStore) Add(ctx context.Context, nonce string, ttl time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	if now.Sub(m.lastSweep) >= nonceSweepInterval {
+		m.lastSweep = now
+		for key, expires := range m.nonces {
+			if !expires.After(now) {
+				delete(m.nonces, key)
+			}
+		}
+	}
+
+	if expires, ok := m.nonces[nonce]; ok && expires.After(now) {
+		return false, nil
+	}
+	m.nonces[nonce] = now.Add(ttl)
+	return true, nil
+}
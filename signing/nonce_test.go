@@ -0,0 +1,29 @@
+package signing
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryNonceStore(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	store := NewMemoryNonceStore()
+	store.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	added, _ := store.Add(ctx, "nonce", time.Minute)
+	assert.True(t, added)
+	added, _ = store.Add(ctx, "nonce", time.Minute)
+	assert.False(t, added)
+
+	now = now.Add(2 * time.Minute)
+	added, _ = store.Add(ctx, "other", time.Minute)
+	assert.True(t, added)
+	assert.Len(t, store.nonces, 1)
+
+	added, _ = store.Add(ctx, "nonce", time.Minute)
+	assert.True(t, added)
+}
@@ -0,0 +1,106 @@
+package signing
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	HeaderKeyId     = "X-Signature-Key-Id"
+	HeaderTimestamp = "X-Signature-Timestamp"
+	HeaderNonce     = "X-Signature-Nonce"
+	HeaderHeaders   = "X-Signature-Headers"
+	HeaderSignature = "X-Signature"
+)
+
+// Signer signs outgoing requests with shared secret.
+//
+// Signature is HMAC-SHA256 over method, host, path with query, timestamp, nonce,
+// selected headers and SHA256 hash of the body. Host is signed, so a request to one
+// service can not be replayed to another service which shares the key. Proxies between
+// client and service should keep the Host header.
+type Signer struct {
+	keyId   string
+	secret  []byte
+	headers []string
+	now     func() time.Time
+}
+
+// Create new Signer. Values of headers are included in signature
+//
+// Example:
+//
+//	signer := signing.NewSigner("key-2024", secret, "Content-Type", logger.HeaderRequestId)
+//	c := client.New(client.WithSigner(signer))
+func NewSigner(keyId string, secret []byte, headers ...string) *Signer {
+	normalized := make([]string, len(headers))
+	for i, header := range headers {
+		normalized[i] = strings.ToLower(header)
+	}
+	return &Signer{
+		keyId:   keyId,
+		secret:  secret,
+		headers: normalized,
+		now:     time.Now,
+	}
+}
+
+// Sign request. body should be the same as body of the request
+func (s *Signer) Sign(r *http.Request, body []byte) error {
+	timestamp := strconv.FormatInt(s.now().Unix(), 10)
+	nonce := uuid.NewString()
+
+	r.Header.Set(HeaderKeyId, s.keyId)
+	r.Header.Set(HeaderTimestamp, timestamp)
+	r.Header.Set(HeaderNonce, nonce)
+	r.Header.Set(HeaderHeaders, strings.Join(s.headers, ";"))
+	r.Header.Set(HeaderSignature, Signature(s.secret, r, s.headers, timestamp, nonce, body))
+	return nil
+}
+
+// Calculate signature of request
+func Signature(secret []byte, r *http.Request, headers []string, timestamp, nonce string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(canonicalString(r, headers, timestamp, nonce, body)))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func canonicalString(r *http.Request, headers []string, timestamp, nonce string, body []byte) string {
+	bodyHash := sha256.Sum256(body)
+
+	var b strings.Builder
+	b.WriteString(strings.ToUpper(r.Method))
+	b.WriteByte('\n')
+	b.WriteString(requestHost(r))
+	b.WriteByte('\n')
+	b.WriteString(r.URL.RequestURI())
+	b.WriteByte('\n')
+	b.WriteString(timestamp)
+	b.WriteByte('\n')
+	b.WriteString(nonce)
+	b.WriteByte('\n')
+	for _, header := range headers {
+		b.WriteString(header)
+		b.WriteByte(':')
+		b.WriteString(strings.TrimSpace(r.Header.Get(header)))
+		b.WriteByte('\n')
+	}
+	b.WriteString(hex.EncodeToString(bodyHash[:]))
+	return b.String()
+}
+
+// Host of request in lower case. Outgoing requests may have only host of URL
+func requestHost(r *http.Request) string {
+	if r.Host != "" {
+		return strings.ToLower(r.Host)
+	}
+	return strings.ToLower(r.URL.Host)
+}
@@ -0,0 +1,200 @@
+package signing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Moranilt/http-utils/client"
+	"github.com/Moranilt/http-utils/logger"
+	"github.com/Moranilt/http-utils/response"
+	"github.com/Moranilt/http-utils/tiny_errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func signedRequest(t testing.TB, signer *Signer, body []byte) *http.Request {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/orders?id=1", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if err := signer.Sign(req, body); err != nil {
+		t.Fatal(err)
+	}
+	return req
+}
+
+func TestVerifier(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	body := []byte(`{"name":"John"}`)
+	signer := NewSigner("key-2", []byte("new secret"), "Content-Type")
+	signer.now = func() time.Time { return now }
+
+	verifier := NewVerifier(VerifierConfig{
+		Keys: Keys{
+			"key-1": []byte("old secret"),
+			"key-2": []byte("new secret"),
+		},
+		Nonces:          NewMemoryNonceStore(),
+		RequiredHeaders: []string{"content-type"},
+		Now:             func() time.Time { return now },
+	})
+
+	t.Run("valid signature", func(t *testing.T) {
+		req := signedRequest(t, signer, body)
+		assert.Nil(t, verifier.Verify(req))
+
+		var readBody bytes.Buffer
+		readBody.ReadFrom(req.Body)
+		assert.Equal(t, body, readBody.Bytes())
+	})
+
+	tests := []struct {
+		name     string
+		modify   func(req *http.Request) *http.Request
+		expected string
+	}{
+		{
+			name: "missing signature",
+			modify: func(req *http.Request) *http.Request {
+				req.Header.Del(HeaderSignature)
+				return req
+			},
+			expected: ErrMissingSignature,
+		},
+		{
+			name: "unknown key",
+			modify: func(req *http.Request) *http.Request {
+				req.Header.Set(HeaderKeyId, "key-0")
+				return req
+			},
+			expected: ErrUnknownKey,
+		},
+		{
+			name: "key of another id",
+			modify: func(req *http.Request) *http.Request {
+				req.Header.Set(HeaderKeyId, "key-1")
+				return req
+			},
+			expected: ErrSignatureMismatch,
+		},
+		{
+			name: "old timestamp",
+			modify: func(req *http.Request) *http.Request {
+				req.Header.Set(HeaderTimestamp, "1699999000")
+				return req
+			},
+			expected: ErrTimestampOutOfWindow,
+		},
+		{
+			name: "modified body",
+			modify: func(req *http.Request) *http.Request {
+				modified := httptest.NewRequest(http.MethodPost, "/orders?id=1", bytes.NewReader([]byte(`{"name":"Eve"}`)))
+				modified.Header = req.Header
+				return modified
+			},
+			expected: ErrSignatureMismatch,
+		},
+		{
+			name: "another host",
+			modify: func(req *http.Request) *http.Request {
+				req.Host = "payments.example.com"
+				return req
+			},
+			expected: ErrSignatureMismatch,
+		},
+		{
+			name: "modified signed header",
+			modify: func(req *http.Request) *http.Request {
+				req.Header.Set("Content-Type", "text/plain")
+				return req
+			},
+			expected: ErrSignatureMismatch,
+		},
+		{
+			name: "required header is not signed",
+			modify: func(req *http.Request) *http.Request {
+				req.Header.Set(HeaderHeaders, "")
+				return req
+			},
+			expected: ErrHeaderNotSigned,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := test.modify(signedRequest(t, signer, body))
+			err := verifier.Verify(req)
+			if assert.NotNil(t, err) {
+				assert.Equal(t, test.expected, err.GetMessage())
+				assert.Equal(t, http.StatusUnauthorized, err.GetHTTPStatus())
+			}
+		})
+	}
+
+	t.Run("replayed request", func(t *testing.T) {
+		req := signedRequest(t, signer, body)
+		replay := req.Clone(context.Background())
+		replay.Body = httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body)).Body
+
+		assert.Nil(t, verifier.Verify(req))
+		err := verifier.Verify(replay)
+		if assert.NotNil(t, err) {
+			assert.Equal(t, ErrReplayedRequest, err.GetMessage())
+		}
+	})
+}
+
+type failingNonceStore struct{}
+
+func (failingNonceStore) Add(ctx context.Context, nonce string, ttl time.Duration) (bool, error) {
+	return false, errors.New("redis: connection refused")
+}
+
+func TestVerifier_NonceStoreError(t *testing.T) {
+	body := []byte(`{"name":"John"}`)
+	verifier := NewVerifier(VerifierConfig{
+		Keys:   Keys{"key": []byte("secret")},
+		Nonces: failingNonceStore{},
+		Logger: logger.NewMock(),
+	})
+
+	err := verifier.Verify(signedRequest(t, NewSigner("key", []byte("secret")), body))
+	if assert.NotNil(t, err) {
+		assert.Equal(t, tiny_errors.ERR_CODE_Unavailable, err.GetCode())
+		assert.Equal(t, http.StatusServiceUnavailable, err.GetHTTPStatus())
+	}
+}
+
+func TestClientWithSigner(t *testing.T) {
+	verifier := NewVerifier(VerifierConfig{
+		Keys:            Keys{"key": []byte("secret")},
+		Nonces:          NewMemoryNonceStore(),
+		RequiredHeaders: []string{logger.HeaderRequestId},
+	})
+
+	server := httptest.NewServer(verifier.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})))
+	defer server.Close()
+
+	ctx := logger.ContextWithRequestId(context.Background(), "request-id")
+
+	c := client.New(client.WithSigner(NewSigner("key", []byte("secret"), logger.HeaderRequestId)))
+	resp, err := c.Post(ctx, server.URL+"/orders", []byte(`{"name":"John"}`), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp, err = client.New().Post(ctx, server.URL+"/orders", []byte(`{"name":"John"}`), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	var body response.DefaultResponse[any, *tiny_errors.Error]
+	err = json.NewDecoder(resp.Body).Decode(&body)
+	assert.NoError(t, err)
+	assert.Equal(t, ERR_CODE_InvalidSignature, body.Error.Code)
+	assert.Equal(t, ErrMissingSignature, body.Error.Message)
+}
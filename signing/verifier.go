@@ -0,0 +1,215 @@
+package signing
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Moranilt/http-utils/logger"
+	"github.com/Moranilt/http-utils/response"
+	"github.com/Moranilt/http-utils/tiny_errors"
+)
+
+const (
+	ERR_CODE_InvalidSignature = 994
+)
+
+const (
+	ErrMissingSignature     = "missing signature"
+	ErrUnknownKey           = "unknown key id"
+	ErrInvalidTimestamp     = "invalid timestamp"
+	ErrTimestampOutOfWindow = "timestamp is out of allowed window"
+	ErrReplayedRequest      = "request is already processed"
+	ErrHeaderNotSigned      = "required header is not signed"
+	ErrSignatureMismatch    = "signature mismatch"
+	ErrUnableToReadBody     = "unable to read request body"
+	ErrUnableToCheckNonce   = "unable to check nonce"
+	ErrBodyTooLarge         = "request body is too large"
+)
+
+func init() {
+	tiny_errors.MustRegister(tiny_errors.Definition{
+		Code:        ERR_CODE_InvalidSignature,
+		Name:        "InvalidSignature",
+		Message:     ErrSignatureMismatch,
+		HTTPStatus:  http.StatusUnauthorized,
+		Severity:    tiny_errors.SeverityWarning,
+		Description: "Signature of request is missing, expired, replayed or does not match",
+	})
+}
+
+const (
+	defaultWindow            = 5 * time.Minute
+	defaultMaxBodySize int64 = 10 << 20
+)
+
+// KeyProvider returns secret by key id
+type KeyProvider interface {
+	Secret(keyId string) ([]byte, bool)
+}
+
+// Active keys by key id. To rotate keys, add a new key, move clients to it and
+// remove the old one.
+type Keys map[string][]byte
+
+func (k Keys) Secret(keyId string) ([]byte, bool) {
+	secret, ok := k[keyId]
+	return secret, ok
+}
+
+type VerifierConfig struct {
+	// Provider of secrets. Required
+	Keys KeyProvider
+
+	// Max difference between timestamp of request and current time. Default is 5 minutes
+	Window time.Duration
+
+	// Store of used nonces. Replay protection by nonce is disabled if nil
+	Nonces NonceStore
+
+	// Headers which must be included in signature
+	RequiredHeaders []string
+
+	// Max size of request body. Default is 10MB
+	MaxBodySize int64
+
+	// Logger to report nonce store errors. Default is logger.Default()
+	Logger logger.Logger
+
+	// Returns current time. Default is time.Now
+	Now func() time.Time
+}
+
+type Verifier struct {
+	cfg VerifierConfig
+}
+
+// Create new Verifier
+func NewVerifier(cfg VerifierConfig) *Verifier {
+	if cfg.Window == 0 {
+		cfg.Window = defaultWindow
+	}
+	if cfg.MaxBodySize == 0 {
+		cfg.MaxBodySize = defaultMaxBodySize
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = logger.Default()
+	}
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+	requiredHeaders := make([]string, len(cfg.RequiredHeaders))
+	for i, header := range cfg.RequiredHeaders {
+		requiredHeaders[i] = strings.ToLower(header)
+	}
+	cfg.RequiredHeaders = requiredHeaders
+	return &Verifier{
+		cfg: cfg,
+	}
+}
+
+// Verify signature of request. Body of request is read and replaced with a new reader,
+// so it can be read again by the next handler.
+//
+// Returns error with status 401 if signature is not valid and error with code
+// tiny_errors.ERR_CODE_Unavailable and status 503 if nonce store fails.
+func (v *Verifier) Verify(r *http.Request) tiny_errors.ErrorHandler {
+	keyId := r.Header.Get(HeaderKeyId)
+	signature := r.Header.Get(HeaderSignature)
+	timestamp := r.Header.Get(HeaderTimestamp)
+	nonce := r.Header.Get(HeaderNonce)
+	if keyId == "" || signature == "" || timestamp == "" || nonce == "" {
+		return unauthorized(ErrMissingSignature)
+	}
+
+	secret, ok := v.cfg.Keys.Secret(keyId)
+	if !ok {
+		return unauthorized(ErrUnknownKey)
+	}
+
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return unauthorized(ErrInvalidTimestamp)
+	}
+	diff := v.cfg.Now().Sub(time.Unix(unix, 0))
+	if diff > v.cfg.Window || diff < -v.cfg.Window {
+		return unauthorized(ErrTimestampOutOfWindow)
+	}
+
+	var headers []string
+	if signed := r.Header.Get(HeaderHeaders); signed != "" {
+		headers = strings.Split(strings.ToLower(signed), ";")
+	}
+	for _, header := range v.cfg.RequiredHeaders {
+		if !slices.Contains(headers, header) {
+			return unauthorized(ErrHeaderNotSigned, tiny_errors.Detail("header", header))
+		}
+	}
+
+	var body []byte
+	if r.Body != nil {
+		body, err = io.ReadAll(io.LimitReader(r.Body, v.cfg.MaxBodySize+1))
+		r.Body.Close()
+		if err != nil {
+			return unauthorized(ErrUnableToReadBody)
+		}
+		if int64(len(body)) > v.cfg.MaxBodySize {
+			return unauthorized(ErrBodyTooLarge)
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	expected := Signature(secret, r, headers, timestamp, nonce, body)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return unauthorized(ErrSignatureMismatch)
+	}
+
+	if v.cfg.Nonces != nil {
+		added, err := v.cfg.Nonces.Add(r.Context(), keyId+":"+nonce, 2*v.cfg.Window)
+		if err != nil {
+			v.cfg.Logger.Error(ErrUnableToCheckNonce, "error", err.Error())
+			return tiny_errors.New(tiny_errors.ERR_CODE_Unavailable)
+		}
+		if !added {
+			return unauthorized(ErrReplayedRequest)
+		}
+	}
+
+	return nil
+}
+
+// Middleware verifies signature of request and responds with status 401 if it is not valid
+// or with status 503 if nonce store is unavailable.
+//
+// Example:
+//
+//	verifier := signing.NewVerifier(signing.VerifierConfig{
+//		Keys: signing.Keys{
+//			"key-2023": oldSecret,
+//			"key-2024": newSecret,
+//		},
+//		Nonces: signing.NewMemoryNonceStore(),
+//	})
+//	router.Use(verifier.Middleware)
+func (v *Verifier) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := v.Verify(r); err != nil {
+			response.ErrorResponse(w, err, err.GetHTTPStatus(), response.Request(r))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func unauthorized(message string, options ...tiny_errors.ErrorOption) tiny_errors.ErrorHandler {
+	options = append([]tiny_errors.ErrorOption{
+		tiny_errors.Message(message),
+		tiny_errors.HTTPStatus(http.StatusUnauthorized),
+	}, options...)
+	return tiny_errors.New(ERR_CODE_InvalidSignature, options...)
+}
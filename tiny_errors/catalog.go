@@ -0,0 +1,182 @@
+package tiny_errors
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	go_json "github.com/goccy/go-json"
+)
+
+type Severity string
+
+const (
+	SeverityInfo     Severity = "info"
+	SeverityWarning  Severity = "warning"
+	SeverityError    Severity = "error"
+	SeverityCritical Severity = "critical"
+)
+
+var (
+	ErrDuplicateCode = errors.New("error code is already registered")
+	ErrDuplicateName = errors.New("error name is already registered")
+)
+
+// Definition of error code in catalog
+type Definition struct {
+	Code int `json:"code"`
+
+	// Unique name of the error, e.g. "UserNotFound". Optional
+	Name string `json:"name,omitempty"`
+
+	// Template of message. Args are set by MessageArgs option
+	Message string `json:"message"`
+
+	// Default HTTP status of the error. If it is 0, status 400 is used
+	HTTPStatus int `json:"http_status"`
+
+	// Default is SeverityError
+	Severity Severity `json:"severity"`
+
+	// Description for client teams: when the error happens and how to handle it
+	Description string `json:"description,omitempty"`
+}
+
+var (
+	catalogStorage atomic.Value
+	catalogMu      sync.Mutex
+)
+
+func loadCatalog() map[int]Definition {
+	catalog, _ := catalogStorage.Load().(map[int]Definition)
+	return catalog
+}
+
+// Register definitions of errors in catalog. Returns an error if code or name is already
+// registered, in this case none of the definitions are registered.
+//
+// Example:
+//
+//	err := tiny_errors.Register(
+//		tiny_errors.Definition{
+//			Code:        ERR_UserNotFound,
+//			Name:        "UserNotFound",
+//			Message:     "User %s not found",
+//			HTTPStatus:  http.StatusNotFound,
+//			Description: "User with requested id does not exist",
+//		},
+//	)
+func Register(definitions ...Definition) error {
+	catalogMu.Lock()
+	defer catalogMu.Unlock()
+
+	catalog := maps.Clone(loadCatalog())
+	if catalog == nil {
+		catalog = make(map[int]Definition, len(definitions))
+	}
+	names := make(map[string]int, len(catalog))
+	for code, definition := range catalog {
+		if definition.Name != "" {
+			names[definition.Name] = code
+		}
+	}
+
+	for _, definition := range definitions {
+		if registered, ok := catalog[definition.Code]; ok {
+			return fmt.Errorf("%w: %d (%s)", ErrDuplicateCode, definition.Code, registered.Message)
+		}
+		if code, ok := names[definition.Name]; ok && definition.Name != "" {
+			return fmt.Errorf("%w: %q of code %d", ErrDuplicateName, definition.Name, code)
+		}
+		if definition.HTTPStatus == 0 {
+			definition.HTTPStatus = http.StatusBadRequest
+		}
+		if definition.Severity == "" {
+			definition.Severity = SeverityError
+		}
+		catalog[definition.Code] = definition
+		if definition.Name != "" {
+			names[definition.Name] = definition.Code
+		}
+	}
+
+	catalogStorage.Store(catalog)
+	return nil
+}
+
+// Same as Register, but panics if code or name is already registered. Should be used in init functions
+func MustRegister(definitions ...Definition) {
+	if err := Register(definitions...); err != nil {
+		panic(err)
+	}
+}
+
+// Get definition of error code
+func Lookup(code int) (Definition, bool) {
+	definition, ok := loadCatalog()[code]
+	return definition, ok
+}
+
+// Severity of error by its code. Returns SeverityError if code is not registered
+func SeverityOf(err ErrorHandler) Severity {
+	if definition, ok := Lookup(err.GetCode()); ok {
+		return definition.Severity
+	}
+	return SeverityError
+}
+
+// All registered definitions sorted by code
+func Definitions() []Definition {
+	catalog := loadCatalog()
+	codes := make([]int, 0, len(catalog))
+	for code := range catalog {
+		codes = append(codes, code)
+	}
+	slices.Sort(codes)
+	definitions := make([]Definition, len(codes))
+	for i, code := range codes {
+		definitions[i] = catalog[code]
+	}
+	return definitions
+}
+
+// Remove all definitions from catalog
+func ResetCatalog() {
+	catalogMu.Lock()
+	catalogStorage.Store(map[int]Definition{})
+	catalogMu.Unlock()
+}
+
+// Export catalog as JSON array of definitions sorted by code
+func ExportJSON() ([]byte, error) {
+	return go_json.MarshalIndent(Definitions(), "", "  ")
+}
+
+// Export catalog as Markdown table sorted by code
+func ExportMarkdown() string {
+	var b strings.Builder
+	b.WriteString("| Code | Name | HTTP status | Severity | Message | Description |\n")
+	b.WriteString("| --- | --- | --- | --- | --- | --- |\n")
+	for _, definition := range Definitions() {
+		b.WriteString("| " + strconv.Itoa(definition.Code))
+		b.WriteString(" | " + escapeMarkdown(definition.Name))
+		b.WriteString(" | " + strconv.Itoa(definition.HTTPStatus) + " " + http.StatusText(definition.HTTPStatus))
+		b.WriteString(" | " + string(definition.Severity))
+		b.WriteString(" | " + escapeMarkdown(definition.Message))
+		b.WriteString(" | " + escapeMarkdown(definition.Description))
+		b.WriteString(" |\n")
+	}
+	return b.String()
+}
+
+// Escape text for cell of Markdown table
+func escapeMarkdown(text string) string {
+	text = strings.ReplaceAll(text, "|", `\|`)
+	return strings.ReplaceAll(text, "\n", "<br>")
+}
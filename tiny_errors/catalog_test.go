@@ -0,0 +1,82 @@
+package tiny_errors
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCatalog(t *testing.T) {
+	saved := loadCatalog()
+	ResetCatalog()
+	t.Cleanup(func() { catalogStorage.Store(saved) })
+
+	err := Register(
+		Definition{
+			Code:        2000,
+			Name:        "UserNotFound",
+			Message:     "User %s not found",
+			HTTPStatus:  http.StatusNotFound,
+			Severity:    SeverityWarning,
+			Description: "User does not exist | was deleted",
+		},
+		Definition{
+			Code:    1000,
+			Message: "Not valid token",
+		},
+	)
+	assert.NoError(t, err)
+
+	t.Run("new error uses definition", func(t *testing.T) {
+		err := New(2000, MessageArgs("John"))
+		assert.Equal(t, "User John not found", err.GetMessage())
+		assert.Equal(t, http.StatusNotFound, err.GetHTTPStatus())
+		assert.Equal(t, SeverityWarning, SeverityOf(err))
+
+		err = New(2000, HTTPStatus(http.StatusGone), Message("gone"))
+		assert.Equal(t, "gone", err.GetMessage())
+		assert.Equal(t, http.StatusGone, err.GetHTTPStatus())
+	})
+
+	t.Run("defaults", func(t *testing.T) {
+		definition, ok := Lookup(1000)
+		assert.True(t, ok)
+		assert.Equal(t, http.StatusBadRequest, definition.HTTPStatus)
+		assert.Equal(t, SeverityError, definition.Severity)
+		assert.Equal(t, SeverityError, SeverityOf(New(1)))
+	})
+
+	t.Run("duplicates", func(t *testing.T) {
+		err := Register(Definition{Code: 3000, Message: "new"}, Definition{Code: 2000, Message: "duplicate"})
+		assert.ErrorIs(t, err, ErrDuplicateCode)
+		_, ok := Lookup(3000)
+		assert.False(t, ok, "definitions should not be registered partially")
+
+		err = Register(Definition{Code: 3000, Name: "UserNotFound"})
+		assert.ErrorIs(t, err, ErrDuplicateName)
+
+		err = Register(Definition{Code: 3000}, Definition{Code: 3000})
+		assert.ErrorIs(t, err, ErrDuplicateCode)
+
+		assert.Panics(t, func() {
+			MustRegister(Definition{Code: 1000})
+		})
+	})
+
+	t.Run("export", func(t *testing.T) {
+		data, err := ExportJSON()
+		assert.NoError(t, err)
+		assert.JSONEq(t, `[
+			{"code":1000,"message":"Not valid token","http_status":400,"severity":"error"},
+			{"code":2000,"name":"UserNotFound","message":"User %s not found","http_status":404,"severity":"warning","description":"User does not exist | was deleted"}
+		]`, string(data))
+
+		assert.Equal(t, ""+
+			"| Code | Name | HTTP status | Severity | Message | Description |\n"+
+			"| --- | --- | --- | --- | --- | --- |\n"+
+			"| 1000 |  | 400 Bad Request | error | Not valid token |  |\n"+
+			"| 2000 | UserNotFound | 404 Not Found | warning | User %s not found | User does not exist \\| was deleted |\n",
+			ExportMarkdown())
+	})
+}
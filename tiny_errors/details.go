@@ -0,0 +1,127 @@
+package tiny_errors
+
+import (
+	"log/slog"
+	"slices"
+	"strconv"
+)
+
+// Returns an ErrorOption that adds a detail of any type to the Error, e.g. number,
+// list or nested object. Value is sent to the client as JSON.
+//
+// Example:
+//
+//	tiny_errors.New(ERR_LimitExceeded, tiny_errors.DetailValue("limit", 100), tiny_errors.DetailValue("allowed", []string{"jpg", "png"}))
+func DetailValue(name string, value any) ErrorOption {
+	return func(setter PropertySetter) {
+		if e, ok := setter.(interface{ SetDetailValue(string, any) }); ok {
+			e.SetDetailValue(name, value)
+			return
+		}
+		if text, ok := value.(string); ok {
+			setter.SetDetail(name, text)
+		}
+	}
+}
+
+// Returns an ErrorOption that adds an internal detail to the Error. Internal details are
+// logged, but never sent to the client.
+//
+// Example:
+//
+//	tiny_errors.Wrap(err, ERR_PaymentFailed, tiny_errors.InternalDetail("provider_response", body))
+func InternalDetail(name string, value any) ErrorOption {
+	return func(setter PropertySetter) {
+		if e, ok := setter.(interface{ SetInternalDetail(string, any) }); ok {
+			e.SetInternalDetail(name, value)
+		}
+	}
+}
+
+// Adds a detail of any type to the Details map of the Error
+func (e *Error) SetDetailValue(name string, value any) {
+	if e.Details == nil {
+		e.Details = make(map[string]any)
+	}
+	e.Details[name] = value
+}
+
+// Adds a detail which is logged, but not sent to the client
+func (e *Error) SetInternalDetail(name string, value any) {
+	if e.internal == nil {
+		e.internal = make(map[string]any)
+	}
+	e.internal[name] = value
+}
+
+// Returns details which are not sent to the client
+func (e *Error) InternalDetails() map[string]any {
+	return e.internal
+}
+
+// LogValue implements slog.LogValuer. Error is logged as group with code, message, HTTP status,
+// public and internal details, cause and stack trace if it was captured.
+func (e *Error) LogValue() slog.Value {
+	attrs := []slog.Attr{
+		slog.Int("code", e.Code),
+		slog.String("message", e.Message),
+		slog.Int("http_status", e.httpStatus),
+	}
+	if len(e.Details) > 0 {
+		attrs = append(attrs, slog.Attr{Key: "details", Value: mapValue(e.Details)})
+	}
+	if len(e.internal) > 0 {
+		attrs = append(attrs, slog.Attr{Key: "internal", Value: mapValue(e.internal)})
+	}
+	if e.cause != nil {
+		attrs = append(attrs, slog.String("cause", e.cause.Error()))
+	}
+	if frames := e.StackTrace(); len(frames) > 0 {
+		stack := make([]string, len(frames))
+		for i, frame := range frames {
+			stack[i] = frame.Function + " " + frame.File + ":" + strconv.Itoa(frame.Line)
+		}
+		attrs = append(attrs, slog.Any("stack", stack))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// Value of err for structured logs. Errors which do not implement slog.LogValuer are
+// logged as group with code, message, HTTP status and details.
+//
+// Example:
+//
+//	log.Error(err.Error(), "error", tiny_errors.LogValue(err))
+func LogValue(err ErrorHandler) slog.Value {
+	if valuer, ok := err.(slog.LogValuer); ok {
+		return valuer.LogValue()
+	}
+	attrs := []slog.Attr{
+		slog.Int("code", err.GetCode()),
+		slog.String("message", err.GetMessage()),
+		slog.Int("http_status", err.GetHTTPStatus()),
+	}
+	if details := err.GetDetails(); len(details) > 0 {
+		attrs = append(attrs, slog.Attr{Key: "details", Value: mapValue(details)})
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// Group of map values sorted by keys. Nested maps become nested groups
+func mapValue(values map[string]any) slog.Value {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	attrs := make([]slog.Attr, len(keys))
+	for i, key := range keys {
+		if nested, ok := values[key].(map[string]any); ok {
+			attrs[i] = slog.Attr{Key: key, Value: mapValue(nested)}
+			continue
+		}
+		attrs[i] = slog.Any(key, values[key])
+	}
+	return slog.GroupValue(attrs...)
+}
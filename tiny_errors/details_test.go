@@ -0,0 +1,71 @@
+package tiny_errors
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetailValue(t *testing.T) {
+	type violation struct {
+		Field string `json:"field"`
+		Rule  string `json:"rule"`
+	}
+	err := New(1,
+		Message("not valid"),
+		DetailValue("limit", 10),
+		DetailValue("allowed", []string{"jpg", "png"}),
+		DetailValue("violations", []violation{{Field: "name", Rule: "required"}}),
+		Detail("name", "John"),
+	)
+
+	assert.Equal(t, 10, err.GetDetails()["limit"])
+	assert.JSONEq(t, `{"code":1,"message":"not valid","details":{"limit":10,"allowed":["jpg","png"],"violations":[{"field":"name","rule":"required"}],"name":"John"}}`, err.JSON())
+}
+
+func TestInternalDetail(t *testing.T) {
+	err := New(1, Message("payment failed"), InternalDetail("provider_response", map[string]any{"status": "declined"}), Detail("order_id", "42"))
+
+	assert.Equal(t, map[string]any{"order_id": "42"}, err.GetDetails())
+	assert.Equal(t, map[string]any{"provider_response": map[string]any{"status": "declined"}}, err.(*Error).InternalDetails())
+	assert.JSONEq(t, `{"code":1,"message":"payment failed","details":{"order_id":"42"}}`, err.JSON())
+	assert.JSONEq(t, `{"code":1,"message":"payment failed","details":{"order_id":"42"}}`, Localize(err, "en").JSON())
+}
+
+func TestLogValue(t *testing.T) {
+	var buf bytes.Buffer
+	log := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && (a.Key == slog.TimeKey || a.Key == slog.LevelKey || a.Key == slog.MessageKey) {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+
+	err := Wrap(errors.New("connection refused"), 1,
+		Message("payment failed"),
+		HTTPStatus(http.StatusBadGateway),
+		DetailValue("order", map[string]any{"id": 42}),
+		InternalDetail("provider", "stripe"),
+	)
+	log.Error(err.Error(), "error", err)
+
+	assert.JSONEq(t, `{"error":{"code":1,"message":"payment failed","http_status":502,"details":{"order":{"id":42}},"internal":{"provider":"stripe"},"cause":"connection refused"}}`, buf.String())
+}
+
+type customError struct {
+	ErrorHandler
+}
+
+func TestLogValueOfErrorHandler(t *testing.T) {
+	err := customError{New(1, Message("custom"), Detail("name", "John"))}
+
+	value := LogValue(err)
+	assert.Equal(t, slog.KindGroup, value.Kind())
+	assert.Equal(t, "[code=1 message=custom http_status=400 details=[name=John]]", value.String())
+}
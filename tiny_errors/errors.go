@@ -3,7 +3,9 @@ package tiny_errors
 import (
 	"encoding/json"
 	"fmt"
+	"maps"
 	"net/http"
+	"slices"
 	"sync/atomic"
 
 	go_json "github.com/goccy/go-json"
@@ -30,11 +32,27 @@ func ErrorStorage() map[int]string {
 
 // Default type for error data
 type Error struct {
-	httpStatus  int
-	httpMessage string
-	Code        int            `json:"code"`
-	Message     string         `json:"message"`
-	Details     map[string]any `json:"details"`
+	httpStatus     int
+	httpMessage    string
+	args           []any
+	defaultMessage string
+	customMessage  bool
+	internal       map[string]any
+	cause          error
+	stack          []uintptr
+	withStack      bool
+	Code           int            `json:"code"`
+	Message        string         `json:"message"`
+	Details        map[string]any `json:"details"`
+}
+
+// Copy of error which can be changed without changing e
+func (e *Error) clone() *Error {
+	c := *e
+	c.args = slices.Clone(e.args)
+	c.internal = maps.Clone(e.internal)
+	c.Details = maps.Clone(e.Details)
+	return &c
 }
 
 // ErrorHandler defines an interface for handling errors that can be converted to JSON.
@@ -63,7 +81,8 @@ type PropertySetter interface {
 	SetHTTPStatus(int)
 }
 
-// Error returns the error message.
+// Error returns the error message. Cause is not included, it is available by errors.Unwrap,
+// LogValue and "%+v" format.
 func (e *Error) Error() string {
 	return e.Message
 }
@@ -105,6 +124,21 @@ func (e *Error) GetDetails() map[string]any {
 	return e.Details
 }
 
+// Returns copy of the Error with message translated to the first available locale.
+// Message is not changed if there is no message for the code in locales or if message
+// is set by Message option and differs from the default message of the code.
+// Args of message are the same as for the original message.
+func (e *Error) Localize(locales ...string) ErrorHandler {
+	localized := e.clone()
+	if e.customMessage {
+		return localized
+	}
+	if text, ok := LocalizedMessage(e.Code, e.args, locales...); ok {
+		localized.Message = text
+	}
+	return localized
+}
+
 // Sets the code field of the Error.
 func (e *Error) SetCode(code int) {
 	e.Code = code
@@ -120,7 +154,9 @@ func (e *Error) SetHTTPStatus(code int) {
 // Sets the message field of the Error, formatting it with fmt.Sprintf if
 // format args are provided.
 func (e *Error) SetMessage(msg string, format ...any) {
+	e.customMessage = msg != e.defaultMessage
 	if len(format) > 0 {
+		e.args = format
 		msg = fmt.Sprintf(msg, format...)
 	}
 	e.Message = msg
@@ -138,6 +174,7 @@ func (e *Error) SetDetail(name, data string) {
 // Formats the message field of the Error using fmt.Sprintf.
 // It replaces any %s and %v specifiers in the message with the provided args.
 func (e *Error) FormatMessage(args ...any) {
+	e.args = args
 	e.Message = fmt.Sprintf(e.Message, args...)
 }
 
@@ -182,24 +219,42 @@ func HTTPStatus(status int) ErrorOption {
 }
 
 // Creates a new ErrorHandler with the given error code and options.
-// The code parameter specifies the error code. If a message is registered
-// for the code in ErrorStorage, it will be used as the default message.
+// The code parameter specifies the error code. If the code is registered
+// in catalog, its message and HTTP status are used by default. Otherwise, if
+// a message is registered for the code in ErrorStorage, it will be used as
+// the default message, otherwise the message of the default locale is used.
 // The options allow configuring additional details like the message, HTTP
 // status, and custom key-value pairs. Returns the configured ErrorHandler.
 func New(code int, options ...ErrorOption) ErrorHandler {
+	return newError(code, nil, options)
+}
+
+func newError(code int, cause error, options []ErrorOption) *Error {
 	err := &Error{
+		cause:       cause,
 		httpStatus:  http.StatusBadRequest,
 		httpMessage: http.StatusText(http.StatusBadRequest),
 		Code:        code,
 	}
 
-	if text, ok := ErrorStorage()[code]; ok {
+	definition, registered := Lookup(code)
+	if registered {
+		err.Message = definition.Message
+		err.SetHTTPStatus(definition.HTTPStatus)
+	} else if text, ok := ErrorStorage()[code]; ok {
+		err.Message = text
+	} else if text, ok := LocalizedMessage(code, nil); ok {
 		err.Message = text
 	}
+	err.defaultMessage = err.Message
 
 	for _, opt := range options {
 		opt(err)
 	}
 
+	if err.withStack || captureStack.Load() {
+		err.stack = callers()
+	}
+
 	return err
 }
@@ -0,0 +1,251 @@
+package tiny_errors
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	go_json "github.com/goccy/go-json"
+)
+
+const (
+	ERR_CODE_Validation = 980
+)
+
+const (
+	ErrValidation = "validation failed"
+)
+
+func init() {
+	MustRegister(Definition{
+		Code:        ERR_CODE_Validation,
+		Name:        "Validation",
+		Message:     ErrValidation,
+		HTTPStatus:  http.StatusBadRequest,
+		Severity:    SeverityInfo,
+		Description: "Fields of request are not valid. Violations are listed in details as \"fields\"",
+	})
+}
+
+// Violation of validation rule by field
+type FieldError struct {
+	// Path of field, e.g. "items[0].name"
+	Field string `json:"field"`
+
+	// Name of violated rule, e.g. "required" or "min"
+	Rule string `json:"rule"`
+
+	Message string `json:"message"`
+
+	// Params of rule, e.g. {"min": 18}. They are available in localized messages as placeholders
+	Params map[string]any `json:"params,omitempty"`
+}
+
+// FieldErrors is an ErrorHandler with list of invalid fields. Fields are sent in details as "fields".
+//
+// Example:
+//
+//	errs := tiny_errors.NewFieldErrors()
+//	if req.Name == "" {
+//		errs.Add("name", "required", "is required", nil)
+//	}
+//	if req.Age < 18 {
+//		errs.Add("age", "min", "must be at least 18", map[string]any{"min": 18})
+//	}
+//	if err := errs.Err(); err != nil {
+//		return nil, err
+//	}
+type FieldErrors struct {
+	message string
+	Fields  []FieldError
+}
+
+// Create list of field errors
+func NewFieldErrors(fields ...FieldError) *FieldErrors {
+	return &FieldErrors{Fields: fields}
+}
+
+// Add violation of rule by field
+func (f *FieldErrors) Add(field, rule, message string, params map[string]any) *FieldErrors {
+	f.Fields = append(f.Fields, FieldError{Field: field, Rule: rule, Message: message, Params: params})
+	return f
+}
+
+// Add field errors of errs. FieldErrors are merged, other errors are skipped.
+// Returns the same FieldErrors.
+func (f *FieldErrors) Merge(errs ...error) *FieldErrors {
+	for _, err := range errs {
+		var fieldErrors *FieldErrors
+		if errors.As(err, &fieldErrors) && fieldErrors != nil {
+			f.Fields = append(f.Fields, fieldErrors.Fields...)
+		}
+	}
+	return f
+}
+
+// Add field errors of errs with prefix prepended to paths of fields, e.g. field "name"
+// with prefix "owner" becomes "owner.name". Used to merge errors of nested objects.
+func (f *FieldErrors) MergePrefixed(prefix string, errs ...error) *FieldErrors {
+	for _, err := range errs {
+		var fieldErrors *FieldErrors
+		if !errors.As(err, &fieldErrors) || fieldErrors == nil {
+			continue
+		}
+		for _, field := range fieldErrors.Fields {
+			field.Field = joinFieldPath(prefix, field.Field)
+			f.Fields = append(f.Fields, field)
+		}
+	}
+	return f
+}
+
+func joinFieldPath(prefix, field string) string {
+	switch {
+	case prefix == "":
+		return field
+	case field == "":
+		return prefix
+	case strings.HasPrefix(field, "["):
+		return prefix + field
+	}
+	return prefix + "." + field
+}
+
+// Returns nil if there are no field errors, otherwise FieldErrors
+func (f *FieldErrors) Err() ErrorHandler {
+	if f == nil || len(f.Fields) == 0 {
+		return nil
+	}
+	return f
+}
+
+// Field errors of field path
+func (f *FieldErrors) Get(field string) []FieldError {
+	var result []FieldError
+	for _, fieldError := range f.Fields {
+		if fieldError.Field == field {
+			result = append(result, fieldError)
+		}
+	}
+	return result
+}
+
+// Returns message and list of fields, e.g. "validation failed: name is required; age must be at least 18"
+func (f *FieldErrors) Error() string {
+	if len(f.Fields) == 0 {
+		return f.GetMessage()
+	}
+	parts := make([]string, len(f.Fields))
+	for i, field := range f.Fields {
+		parts[i] = strings.TrimSpace(field.Field + " " + field.Message)
+	}
+	return f.GetMessage() + ": " + strings.Join(parts, "; ")
+}
+
+func (f *FieldErrors) GetHTTPStatus() int {
+	if definition, ok := Lookup(ERR_CODE_Validation); ok {
+		return definition.HTTPStatus
+	}
+	return http.StatusBadRequest
+}
+
+func (f *FieldErrors) GetHTTPMessage() string {
+	return http.StatusText(f.GetHTTPStatus())
+}
+
+func (f *FieldErrors) GetCode() int {
+	return ERR_CODE_Validation
+}
+
+// Returns message of code ERR_CODE_Validation from catalog if FieldErrors is not localized
+func (f *FieldErrors) GetMessage() string {
+	if f.message != "" {
+		return f.message
+	}
+	if definition, ok := Lookup(ERR_CODE_Validation); ok {
+		return definition.Message
+	}
+	return ErrValidation
+}
+
+// Returns list of fields as "fields"
+func (f *FieldErrors) GetDetails() map[string]any {
+	fields := f.Fields
+	if fields == nil {
+		fields = []FieldError{}
+	}
+	return map[string]any{"fields": fields}
+}
+
+func (f *FieldErrors) MarshalJSON() ([]byte, error) {
+	return go_json.Marshal(f.body())
+}
+
+// Converts the FieldErrors to a JSON string.
+func (f *FieldErrors) JSON() string {
+	b, _ := go_json.Marshal(f.body())
+	return string(b)
+}
+
+// Converts the FieldErrors to a JSON string.
+func (f *FieldErrors) JSONOrigin() string {
+	b, _ := json.Marshal(f.body())
+	return string(b)
+}
+
+// Body of FieldErrors in the same format as Error
+func (f *FieldErrors) body() *Error {
+	return &Error{Code: f.GetCode(), Message: f.GetMessage(), Details: f.GetDetails()}
+}
+
+// Reports whether target is an ErrorHandler with code ERR_CODE_Validation
+func (f *FieldErrors) Is(target error) bool {
+	var handler ErrorHandler
+	if !errors.As(target, &handler) {
+		return false
+	}
+	return handler.GetCode() == ERR_CODE_Validation
+}
+
+// Returns copy of FieldErrors with message and messages of fields translated to the first available locale.
+// Messages of fields are found by rules, see RegisterRuleMessages. Messages which are not found are not changed.
+func (f *FieldErrors) Localize(locales ...string) ErrorHandler {
+	l := loadLocales()
+	localized := &FieldErrors{message: f.message, Fields: make([]FieldError, len(f.Fields))}
+	if text, ok := l.template(ERR_CODE_Validation, locales); ok {
+		localized.message = text
+	}
+	for i, field := range f.Fields {
+		if template, ok := l.ruleTemplate(field.Rule, locales); ok {
+			field.Message = formatRuleMessage(template, field)
+		}
+		localized.Fields[i] = field
+	}
+	return localized
+}
+
+// Replace placeholders of template with params and path of field
+func formatRuleMessage(template string, field FieldError) string {
+	if !strings.Contains(template, "{") {
+		return template
+	}
+	replacements := make([]string, 0, len(field.Params)*2+2)
+	replacements = append(replacements, "{field}", field.Field)
+	for name, value := range field.Params {
+		replacements = append(replacements, "{"+name+"}", fmt.Sprint(value))
+	}
+	return strings.NewReplacer(replacements...).Replace(template)
+}
+
+// LogValue implements slog.LogValuer
+func (f *FieldErrors) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Int("code", ERR_CODE_Validation),
+		slog.String("message", f.GetMessage()),
+		slog.Int("http_status", f.GetHTTPStatus()),
+		slog.Any("fields", f.Fields),
+	)
+}
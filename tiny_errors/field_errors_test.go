@@ -0,0 +1,69 @@
+package tiny_errors
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFieldErrors(t *testing.T) {
+	errs := NewFieldErrors()
+	assert.Nil(t, errs.Err())
+
+	errs.Add("name", "required", "is required", nil).
+		Add("age", "min", "must be at least 18", map[string]any{"min": 18})
+
+	err := errs.Err()
+	assert.Equal(t, ERR_CODE_Validation, err.GetCode())
+	assert.Equal(t, ErrValidation, err.GetMessage())
+	assert.Equal(t, http.StatusBadRequest, err.GetHTTPStatus())
+	assert.Equal(t, "validation failed: name is required; age must be at least 18", err.Error())
+	assert.JSONEq(t, `{"code":980,"message":"validation failed","details":{"fields":[
+		{"field":"name","rule":"required","message":"is required"},
+		{"field":"age","rule":"min","message":"must be at least 18","params":{"min":18}}
+	]}}`, err.JSON())
+	assert.Equal(t, err.JSON(), err.JSONOrigin())
+	assert.Len(t, errs.Get("age"), 1)
+	assert.True(t, errors.Is(fmt.Errorf("create user: %w", err), New(ERR_CODE_Validation)))
+}
+
+func TestFieldErrorsMerge(t *testing.T) {
+	body := NewFieldErrors(FieldError{Field: "name", Rule: "required", Message: "is required"})
+	query := NewFieldErrors(FieldError{Field: "page", Rule: "min", Message: "must be at least 1"})
+	owner := NewFieldErrors(
+		FieldError{Field: "email", Rule: "email", Message: "is not valid email"},
+		FieldError{Field: "[0]", Rule: "required", Message: "is required"},
+	)
+
+	errs := NewFieldErrors().
+		Merge(body, fmt.Errorf("query: %w", query), errors.New("not field errors"), nil).
+		MergePrefixed("owner", owner)
+
+	var fields []string
+	for _, field := range errs.Fields {
+		fields = append(fields, field.Field)
+	}
+	assert.Equal(t, []string{"name", "page", "owner.email", "owner[0]"}, fields)
+}
+
+func TestFieldErrorsLocalize(t *testing.T) {
+	t.Cleanup(ResetLocales)
+	RegisterLocale("de", map[int]string{ERR_CODE_Validation: "Validierung fehlgeschlagen"})
+	RegisterRuleMessages("de", map[string]string{"min": "{field} muss mindestens {min} sein"})
+
+	errs := NewFieldErrors().
+		Add("name", "required", "is required", nil).
+		Add("age", "min", "must be at least 18", map[string]any{"min": 18})
+
+	localized := Localize(errs, "de-AT")
+	assert.Equal(t, "Validierung fehlgeschlagen", localized.GetMessage())
+	assert.Equal(t, []FieldError{
+		{Field: "name", Rule: "required", Message: "is required"},
+		{Field: "age", Rule: "min", Message: "age muss mindestens 18 sein", Params: map[string]any{"min": 18}},
+	}, localized.(*FieldErrors).Fields)
+	assert.Equal(t, "must be at least 18", errs.Fields[1].Message)
+	assert.Equal(t, ErrValidation, errs.GetMessage())
+}
@@ -0,0 +1,309 @@
+package tiny_errors
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"maps"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"gopkg.in/yaml.v3"
+)
+
+type locales struct {
+	catalogs      map[string]map[int]string
+	rules         map[string]map[string]string
+	fallbacks     map[string][]string
+	defaultLocale string
+}
+
+var (
+	localeStorage atomic.Value
+	localeMu      sync.Mutex
+)
+
+func init() {
+	localeStorage.Store(&locales{
+		catalogs:  make(map[string]map[int]string),
+		rules:     make(map[string]map[string]string),
+		fallbacks: make(map[string][]string),
+	})
+}
+
+func loadLocales() *locales {
+	return localeStorage.Load().(*locales)
+}
+
+// Change copy of locales and store it
+func updateLocales(update func(l *locales)) {
+	localeMu.Lock()
+	defer localeMu.Unlock()
+
+	current := loadLocales()
+	next := &locales{
+		catalogs:      maps.Clone(current.catalogs),
+		rules:         maps.Clone(current.rules),
+		fallbacks:     maps.Clone(current.fallbacks),
+		defaultLocale: current.defaultLocale,
+	}
+	update(next)
+	localeStorage.Store(next)
+}
+
+// Normalize language tag, e.g. "en_US" becomes "en-us"
+func normalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+}
+
+// Register messages of locale. Messages are merged with messages registered before.
+// Messages are templates for fmt.Sprintf, args are set by MessageArgs or Message options.
+//
+// Example:
+//
+//	tiny_errors.RegisterLocale("en", map[int]string{ERR_UserNotFound: "User %s not found"})
+//	tiny_errors.RegisterLocale("de", map[int]string{ERR_UserNotFound: "Benutzer %s nicht gefunden"})
+func RegisterLocale(locale string, messages map[int]string) {
+	locale = normalizeLocale(locale)
+	updateLocales(func(l *locales) {
+		catalog := maps.Clone(l.catalogs[locale])
+		if catalog == nil {
+			catalog = make(map[int]string, len(messages))
+		}
+		maps.Copy(catalog, messages)
+		l.catalogs[locale] = catalog
+	})
+}
+
+// Register messages of locale from JSON object with codes as keys
+//
+// Example:
+//
+//	{"1000": "Not valid token", "2000": "User %s not found"}
+func RegisterLocaleJSON(locale string, data []byte) error {
+	var messages map[string]string
+	if err := json.Unmarshal(data, &messages); err != nil {
+		return fmt.Errorf("unable to parse messages of locale %q: %w", locale, err)
+	}
+	return registerLocaleMessages(locale, messages)
+}
+
+// Register messages of locale from YAML map with codes as keys
+//
+// Example:
+//
+//	1000: Not valid token
+//	2000: User %s not found
+func RegisterLocaleYAML(locale string, data []byte) error {
+	var messages map[string]string
+	if err := yaml.Unmarshal(data, &messages); err != nil {
+		return fmt.Errorf("unable to parse messages of locale %q: %w", locale, err)
+	}
+	return registerLocaleMessages(locale, messages)
+}
+
+func registerLocaleMessages(locale string, messages map[string]string) error {
+	catalog := make(map[int]string, len(messages))
+	for key, message := range messages {
+		code, err := strconv.Atoi(strings.TrimSpace(key))
+		if err != nil {
+			return fmt.Errorf("not valid code %q of locale %q: %w", key, locale, err)
+		}
+		catalog[code] = message
+	}
+	RegisterLocale(locale, catalog)
+	return nil
+}
+
+// Register messages of locales from files of dir. Name of file without extension is a locale,
+// e.g. "en.json", "de.yaml", "pt-BR.yml". Files with other extensions are skipped.
+//
+// Example:
+//
+//	//go:embed locales
+//	var localesFS embed.FS
+//
+//	err := tiny_errors.RegisterLocalesFS(localesFS, "locales")
+func RegisterLocalesFS(fsys fs.FS, dir string) error {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		ext := path.Ext(entry.Name())
+		locale := strings.TrimSuffix(entry.Name(), ext)
+
+		var register func(locale string, data []byte) error
+		switch strings.ToLower(ext) {
+		case ".json":
+			register = RegisterLocaleJSON
+		case ".yaml", ".yml":
+			register = RegisterLocaleYAML
+		default:
+			continue
+		}
+
+		data, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return err
+		}
+		if err := register(locale, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Set locale which is used when message is not found in requested locales and their fallbacks
+func SetDefaultLocale(locale string) {
+	locale = normalizeLocale(locale)
+	updateLocales(func(l *locales) {
+		l.defaultLocale = locale
+	})
+}
+
+// Set locales which are checked when message is not found in locale, e.g. "uk" falls back to "ru".
+// Parent locale is always checked first, e.g. "en" for "en-us".
+func SetLocaleFallback(locale string, fallbacks ...string) {
+	locale = normalizeLocale(locale)
+	normalized := make([]string, len(fallbacks))
+	for i, fallback := range fallbacks {
+		normalized[i] = normalizeLocale(fallback)
+	}
+	updateLocales(func(l *locales) {
+		l.fallbacks[locale] = normalized
+	})
+}
+
+// Register messages of validation rules for locale. Messages are merged with messages registered before.
+// Placeholders in braces are replaced with params of FieldError, "{field}" is replaced with path of field.
+//
+// Example:
+//
+//	tiny_errors.RegisterRuleMessages("en", map[string]string{"required": "is required", "min": "must be at least {min}"})
+//	tiny_errors.RegisterRuleMessages("de", map[string]string{"required": "ist erforderlich", "min": "muss mindestens {min} sein"})
+func RegisterRuleMessages(locale string, messages map[string]string) {
+	locale = normalizeLocale(locale)
+	updateLocales(func(l *locales) {
+		rules := maps.Clone(l.rules[locale])
+		if rules == nil {
+			rules = make(map[string]string, len(messages))
+		}
+		maps.Copy(rules, messages)
+		l.rules[locale] = rules
+	})
+}
+
+// Remove all registered locales, fallbacks and default locale
+func ResetLocales() {
+	updateLocales(func(l *locales) {
+		l.catalogs = make(map[string]map[int]string)
+		l.rules = make(map[string]map[string]string)
+		l.fallbacks = make(map[string][]string)
+		l.defaultLocale = ""
+	})
+}
+
+// Find message template of code for the first matched locale of the chain
+func (l *locales) template(code int, chain []string) (string, bool) {
+	return l.find(chain, func(locale string) (string, bool) {
+		message, ok := l.catalogs[locale][code]
+		return message, ok
+	})
+}
+
+// Find message template of validation rule for the first matched locale of the chain
+func (l *locales) ruleTemplate(rule string, chain []string) (string, bool) {
+	return l.find(chain, func(locale string) (string, bool) {
+		message, ok := l.rules[locale][rule]
+		return message, ok
+	})
+}
+
+// Find message for the first matched locale of the chain, its parents, fallbacks and default locale
+func (l *locales) find(chain []string, lookup func(locale string) (string, bool)) (string, bool) {
+	visited := make(map[string]struct{})
+	var find func(locale string) (string, bool)
+	find = func(locale string) (string, bool) {
+		if _, ok := visited[locale]; ok || locale == "" {
+			return "", false
+		}
+		visited[locale] = struct{}{}
+
+		if message, ok := lookup(locale); ok {
+			return message, true
+		}
+		if parent, _, ok := strings.Cut(locale, "-"); ok {
+			if message, ok := find(parent); ok {
+				return message, true
+			}
+		}
+		for _, fallback := range l.fallbacks[locale] {
+			if message, ok := find(fallback); ok {
+				return message, true
+			}
+		}
+		return "", false
+	}
+
+	for _, locale := range chain {
+		if message, ok := find(normalizeLocale(locale)); ok {
+			return message, true
+		}
+	}
+	return find(l.defaultLocale)
+}
+
+// Message of code in the first available locale, formatted with args. Template is returned
+// unformatted if there are no args.
+// Returns false if message is not registered in any of locales, their fallbacks and default locale,
+// or if args do not match verbs of template.
+func LocalizedMessage(code int, args []any, locales ...string) (string, bool) {
+	template, ok := loadLocales().template(code, locales)
+	if !ok {
+		return "", false
+	}
+	if len(args) == 0 {
+		return template, true
+	}
+	text := fmt.Sprintf(template, args...)
+	if strings.Contains(text, "%!") {
+		return "", false
+	}
+	return text, true
+}
+
+// Localizer is implemented by errors which can translate messages
+type Localizer interface {
+	Localize(locales ...string) ErrorHandler
+}
+
+// Translate message of err to the first available locale. Errors which do not implement
+// Localizer are returned as is.
+func Localize(err ErrorHandler, locales ...string) ErrorHandler {
+	if localizer, ok := err.(Localizer); ok {
+		return localizer.Localize(locales...)
+	}
+	return err
+}
+
+type localeKey struct{}
+
+// Store locale of the client in context. It has priority over Accept-Language header
+func ContextWithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+// Locale stored in context by ContextWithLocale
+func LocaleFromContext(ctx context.Context) string {
+	locale, _ := ctx.Value(localeKey{}).(string)
+	return locale
+}
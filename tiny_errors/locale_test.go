@@ -0,0 +1,144 @@
+package tiny_errors
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalize(t *testing.T) {
+	t.Cleanup(ResetLocales)
+
+	const (
+		ErrCodeUserNotFound = 2000
+		ErrCodeForbidden    = 2001
+	)
+
+	RegisterLocale("en", map[int]string{
+		ErrCodeUserNotFound: "User %s not found",
+		ErrCodeForbidden:    "Forbidden",
+	})
+	RegisterLocale("de", map[int]string{ErrCodeUserNotFound: "Benutzer %s nicht gefunden"})
+	RegisterLocale("ru", map[int]string{ErrCodeForbidden: "Доступ запрещен"})
+	SetLocaleFallback("uk", "ru")
+	SetDefaultLocale("en")
+
+	err := New(ErrCodeUserNotFound, MessageArgs("John"))
+	assert.Equal(t, "User John not found", err.GetMessage())
+	mismatch := New(ErrCodeUserNotFound, MessageArgs("Ann", 42))
+
+	tests := []struct {
+		name     string
+		err      ErrorHandler
+		locales  []string
+		expected string
+	}{
+		{
+			name:     "exact locale",
+			err:      err,
+			locales:  []string{"de"},
+			expected: "Benutzer John nicht gefunden",
+		},
+		{
+			name:     "parent locale",
+			err:      err,
+			locales:  []string{"de_AT"},
+			expected: "Benutzer John nicht gefunden",
+		},
+		{
+			name:     "next locale of chain",
+			err:      err,
+			locales:  []string{"fr", "de"},
+			expected: "Benutzer John nicht gefunden",
+		},
+		{
+			name:     "fallback locale",
+			err:      New(ErrCodeForbidden),
+			locales:  []string{"uk-UA"},
+			expected: "Доступ запрещен",
+		},
+		{
+			name:     "default locale",
+			err:      New(ErrCodeForbidden),
+			locales:  []string{"de"},
+			expected: "Forbidden",
+		},
+		{
+			name:     "message args",
+			err:      New(ErrCodeUserNotFound, Message("User %s not found", "Ann")),
+			locales:  []string{"de"},
+			expected: "Benutzer Ann nicht gefunden",
+		},
+		{
+			name:     "custom message",
+			err:      New(ErrCodeUserNotFound, Message("user %s is blocked", "Ann")),
+			locales:  []string{"de"},
+			expected: "user Ann is blocked",
+		},
+		{
+			name:     "template without args",
+			err:      New(ErrCodeUserNotFound),
+			locales:  []string{"de"},
+			expected: "Benutzer %s nicht gefunden",
+		},
+		{
+			name:     "args do not match template",
+			err:      mismatch,
+			locales:  []string{"de"},
+			expected: mismatch.GetMessage(),
+		},
+		{
+			name:     "not registered code",
+			err:      New(1, Message("custom message")),
+			locales:  []string{"de"},
+			expected: "custom message",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			localized := Localize(test.err, test.locales...)
+			assert.Equal(t, test.expected, localized.GetMessage())
+			assert.Equal(t, test.err.GetCode(), localized.GetCode())
+		})
+	}
+
+	assert.Equal(t, "User John not found", err.GetMessage(), "original error should not be changed")
+
+	withDetails := New(ErrCodeUserNotFound, MessageArgs("John"), Detail("user_id", "1"))
+	localized := Localize(withDetails, "de").(*Error)
+	localized.SetDetail("locale", "de")
+	assert.Equal(t, map[string]any{"user_id": "1"}, withDetails.GetDetails(), "details of original error should not be changed")
+}
+
+func TestRegisterLocalesFS(t *testing.T) {
+	t.Cleanup(ResetLocales)
+
+	fsys := fstest.MapFS{
+		"locales/en.json":    {Data: []byte(`{"1": "Not valid token", "2": "Token expired"}`)},
+		"locales/pt-BR.yaml": {Data: []byte("1: Token inválido\n")},
+		"locales/README.md":  {Data: []byte("# Locales")},
+	}
+	assert.NoError(t, RegisterLocalesFS(fsys, "locales"))
+
+	message, ok := LocalizedMessage(1, nil, "pt-br")
+	assert.True(t, ok)
+	assert.Equal(t, "Token inválido", message)
+
+	message, ok = LocalizedMessage(2, nil, "en-US")
+	assert.True(t, ok)
+	assert.Equal(t, "Token expired", message)
+
+	_, ok = LocalizedMessage(2, nil, "pt-BR")
+	assert.False(t, ok)
+
+	assert.Error(t, RegisterLocaleJSON("en", []byte(`{"code": "message"}`)))
+	assert.Error(t, RegisterLocaleYAML("en", []byte(`: :`)))
+}
+
+func TestLocaleContext(t *testing.T) {
+	assert.Empty(t, LocaleFromContext(context.Background()))
+	assert.Equal(t, "de", LocaleFromContext(ContextWithLocale(context.Background(), "de")))
+}
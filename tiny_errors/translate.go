@@ -0,0 +1,169 @@
+package tiny_errors
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net"
+	"net/http"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"syscall"
+)
+
+const (
+	ERR_CODE_NotFound      = 988
+	ERR_CODE_Timeout       = 987
+	ERR_CODE_Canceled      = 986
+	ERR_CODE_Unavailable   = 985
+	ERR_CODE_Conflict      = 984
+	ERR_CODE_Unprocessable = 983
+	ERR_CODE_Internal      = 982
+)
+
+const (
+	ErrNotFound      = "not found"
+	ErrTimeout       = "request timeout"
+	ErrCanceled      = "request is canceled"
+	ErrUnavailable   = "service is unavailable"
+	ErrConflict      = "conflict"
+	ErrUnprocessable = "unprocessable entity"
+	ErrInternal      = "internal error"
+)
+
+func init() {
+	MustRegister(
+		Definition{Code: ERR_CODE_NotFound, Name: "NotFound", Message: ErrNotFound, HTTPStatus: http.StatusNotFound, Severity: SeverityInfo, Description: "Requested entity does not exist"},
+		Definition{Code: ERR_CODE_Timeout, Name: "Timeout", Message: ErrTimeout, HTTPStatus: http.StatusGatewayTimeout, Description: "Operation is not finished in time"},
+		Definition{Code: ERR_CODE_Canceled, Name: "Canceled", Message: ErrCanceled, HTTPStatus: http.StatusRequestTimeout, Severity: SeverityInfo, Description: "Request is canceled by the client"},
+		Definition{Code: ERR_CODE_Unavailable, Name: "Unavailable", Message: ErrUnavailable, HTTPStatus: http.StatusServiceUnavailable, Severity: SeverityCritical, Description: "Dependency of the service is not available. Request can be retried"},
+		Definition{Code: ERR_CODE_Conflict, Name: "Conflict", Message: ErrConflict, HTTPStatus: http.StatusConflict, Severity: SeverityWarning, Description: "Entity already exists or is changed concurrently"},
+		Definition{Code: ERR_CODE_Unprocessable, Name: "Unprocessable", Message: ErrUnprocessable, HTTPStatus: http.StatusUnprocessableEntity, Severity: SeverityWarning, Description: "Entity violates constraints, e.g. references not existing entity"},
+		Definition{Code: ERR_CODE_Internal, Name: "Internal", Message: ErrInternal, HTTPStatus: http.StatusInternalServerError, Description: "Unexpected error"},
+	)
+	RegisterTranslator(TranslateNet, TranslateContext, TranslateSQL)
+}
+
+// Translator converts err to ErrorHandler. Returns nil if err is not supported by translator
+type Translator func(err error) ErrorHandler
+
+var (
+	translatorStorage atomic.Value
+	translatorMu      sync.Mutex
+)
+
+func loadTranslators() []Translator {
+	translators, _ := translatorStorage.Load().([]Translator)
+	return translators
+}
+
+// Register translators. Translators registered later have priority over translators registered before,
+// so services can override built-in translators.
+//
+// Example:
+//
+//	tiny_errors.RegisterTranslator(func(err error) tiny_errors.ErrorHandler {
+//		if errors.Is(err, storage.ErrObjectNotFound) {
+//			return tiny_errors.Wrap(err, ERR_FileNotFound, tiny_errors.HTTPStatus(http.StatusNotFound))
+//		}
+//		return nil
+//	})
+func RegisterTranslator(translators ...Translator) {
+	translatorMu.Lock()
+	defer translatorMu.Unlock()
+
+	registered := slices.Clone(translators)
+	slices.Reverse(registered)
+	translatorStorage.Store(append(registered, loadTranslators()...))
+}
+
+// Convert err to ErrorHandler using registered translators. Options are applied to the result.
+//
+// If err already is an ErrorHandler, options are applied to its copy, so the original error is not
+// changed. Without options it is returned as is. If no translator supports err,
+// it is wrapped into error with code ERR_CODE_Internal and status 500.
+// Returns nil if err is nil.
+//
+// Built-in translators support database/sql, context and net errors. Packages clients/database
+// and clients/redis register translators of lib/pq and go-redis errors.
+//
+// Example:
+//
+//	user, err := repo.GetUser(ctx, id)
+//	if err != nil {
+//		return nil, tiny_errors.Translate(err, tiny_errors.Detail("user_id", id))
+//	}
+func Translate(err error, options ...ErrorOption) ErrorHandler {
+	if err == nil {
+		return nil
+	}
+
+	var handler ErrorHandler
+	if errors.As(err, &handler) {
+		if e, ok := handler.(*Error); ok && len(options) > 0 {
+			handler = e.clone()
+		}
+		return applyOptions(handler, options)
+	}
+
+	for _, translate := range loadTranslators() {
+		if handler = translate(err); handler != nil {
+			break
+		}
+	}
+	if handler == nil {
+		handler = Wrap(err, ERR_CODE_Internal, HTTPStatus(http.StatusInternalServerError), Message(ErrInternal))
+	}
+	return applyOptions(handler, options)
+}
+
+func applyOptions(handler ErrorHandler, options []ErrorOption) ErrorHandler {
+	if setter, ok := handler.(PropertySetter); ok {
+		for _, opt := range options {
+			opt(setter)
+		}
+	}
+	return handler
+}
+
+// Translate errors of database/sql
+func TranslateSQL(err error) ErrorHandler {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return Wrap(err, ERR_CODE_NotFound, HTTPStatus(http.StatusNotFound), Message(ErrNotFound))
+	case errors.Is(err, sql.ErrConnDone), errors.Is(err, driver.ErrBadConn):
+		return Wrap(err, ERR_CODE_Unavailable, HTTPStatus(http.StatusServiceUnavailable), Message(ErrUnavailable))
+	}
+	return nil
+}
+
+// Translate context.DeadlineExceeded and context.Canceled
+func TranslateContext(err error) ErrorHandler {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return Wrap(err, ERR_CODE_Timeout, HTTPStatus(http.StatusGatewayTimeout), Message(ErrTimeout))
+	case errors.Is(err, context.Canceled):
+		return Wrap(err, ERR_CODE_Canceled, HTTPStatus(http.StatusRequestTimeout), Message(ErrCanceled))
+	}
+	return nil
+}
+
+// Translate timeouts and connection errors of net package
+func TranslateNet(err error) ErrorHandler {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return Wrap(err, ERR_CODE_Timeout, HTTPStatus(http.StatusGatewayTimeout), Message(ErrTimeout))
+	}
+
+	var (
+		opErr  *net.OpError
+		dnsErr *net.DNSError
+	)
+	if errors.As(err, &opErr) || errors.As(err, &dnsErr) ||
+		errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) {
+		return Wrap(err, ERR_CODE_Unavailable, HTTPStatus(http.StatusServiceUnavailable), Message(ErrUnavailable))
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package tiny_errors
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTranslate(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		code    int
+		status  int
+		message string
+	}{
+		{"no rows", fmt.Errorf("get user: %w", sql.ErrNoRows), ERR_CODE_NotFound, http.StatusNotFound, ErrNotFound},
+		{"conn done", sql.ErrConnDone, ERR_CODE_Unavailable, http.StatusServiceUnavailable, ErrUnavailable},
+		{"deadline", context.DeadlineExceeded, ERR_CODE_Timeout, http.StatusGatewayTimeout, ErrTimeout},
+		{"canceled", fmt.Errorf("query: %w", context.Canceled), ERR_CODE_Canceled, http.StatusRequestTimeout, ErrCanceled},
+		{"net timeout", &net.OpError{Op: "dial", Err: os.ErrDeadlineExceeded}, ERR_CODE_Timeout, http.StatusGatewayTimeout, ErrTimeout},
+		{"connection refused", &net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}, ERR_CODE_Unavailable, http.StatusServiceUnavailable, ErrUnavailable},
+		{"dns", &net.DNSError{Err: "no such host", Name: "db"}, ERR_CODE_Unavailable, http.StatusServiceUnavailable, ErrUnavailable},
+		{"unknown", errors.New("boom"), ERR_CODE_Internal, http.StatusInternalServerError, ErrInternal},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := Translate(test.err)
+
+			assert.Equal(t, test.code, err.GetCode())
+			assert.Equal(t, test.status, err.GetHTTPStatus())
+			assert.Equal(t, test.message, err.GetMessage())
+			assert.True(t, errors.Is(err, test.err))
+		})
+	}
+}
+
+func TestTranslateErrorHandler(t *testing.T) {
+	assert.Nil(t, Translate(nil))
+
+	original := New(2000, Message("user not found"))
+	assert.Same(t, original, Translate(fmt.Errorf("service: %w", original)))
+}
+
+func TestTranslateOptions(t *testing.T) {
+	err := Translate(sql.ErrNoRows, Detail("user_id", "42"), Message("user not found"))
+
+	assert.Equal(t, ERR_CODE_NotFound, err.GetCode())
+	assert.Equal(t, "user not found", err.GetMessage())
+	assert.Equal(t, map[string]any{"user_id": "42"}, err.GetDetails())
+}
+
+func TestRegisterTranslator(t *testing.T) {
+	saved := loadTranslators()
+	t.Cleanup(func() { translatorStorage.Store(saved) })
+
+	errStorage := errors.New("object not found")
+	RegisterTranslator(
+		func(err error) ErrorHandler {
+			if errors.Is(err, errStorage) {
+				return Wrap(err, 3000, HTTPStatus(http.StatusNotFound))
+			}
+			return nil
+		},
+		func(err error) ErrorHandler {
+			if errors.Is(err, sql.ErrNoRows) {
+				return Wrap(err, 3001, HTTPStatus(http.StatusNotFound))
+			}
+			return nil
+		},
+	)
+
+	assert.Equal(t, 3000, Translate(errStorage).GetCode())
+	assert.Equal(t, 3001, Translate(sql.ErrNoRows).GetCode())
+	assert.Equal(t, ERR_CODE_Timeout, Translate(context.DeadlineExceeded).GetCode())
+}
+
+func TestTranslateErrorHandlerOptions(t *testing.T) {
+	original := New(2000, Message("user not found"), Detail("source", "db"))
+	err := Translate(fmt.Errorf("service: %w", original), Detail("user_id", "42"))
+
+	assert.NotSame(t, original, err)
+	assert.Equal(t, 2000, err.GetCode())
+	assert.Equal(t, "user not found", err.GetMessage())
+	assert.Equal(t, map[string]any{"source": "db", "user_id": "42"}, err.GetDetails())
+	assert.Equal(t, map[string]any{"source": "db"}, original.GetDetails())
+}
@@ -0,0 +1,122 @@
+package tiny_errors
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"strconv"
+	"sync/atomic"
+)
+
+const maxStackDepth = 32
+
+var captureStack atomic.Bool
+
+// Capture stack trace of every created error. Disabled by default
+func CaptureStack(enabled bool) {
+	captureStack.Store(enabled)
+}
+
+// Creates a new ErrorHandler with the given code and options which wraps err.
+// err is available through errors.Is/As and printed by "%+v", but it is never
+// sent to the client by JSON() and JSONOrigin().
+//
+// Example:
+//
+//	user, err := repo.GetUser(ctx, id)
+//	if errors.Is(err, sql.ErrNoRows) {
+//		return nil, tiny_errors.Wrap(err, ERR_UserNotFound, tiny_errors.HTTPStatus(http.StatusNotFound))
+//	}
+func Wrap(err error, code int, options ...ErrorOption) ErrorHandler {
+	return newError(code, err, options)
+}
+
+// Returns an ErrorOption that sets the cause of the Error
+func WithCause(err error) ErrorOption {
+	return func(setter PropertySetter) {
+		if e, ok := setter.(interface{ SetCause(error) }); ok {
+			e.SetCause(err)
+		}
+	}
+}
+
+// Returns an ErrorOption that captures stack trace of the Error even if CaptureStack is disabled
+func WithStack() ErrorOption {
+	return func(setter PropertySetter) {
+		if e, ok := setter.(*Error); ok {
+			e.withStack = true
+		}
+	}
+}
+
+// Sets the cause of the Error
+func (e *Error) SetCause(err error) {
+	e.cause = err
+}
+
+// Returns the cause of the Error
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+// Reports whether target is an ErrorHandler with the same code
+//
+// Example:
+//
+//	var ErrUserNotFound = tiny_errors.New(ERR_UserNotFound)
+//	errors.Is(err, ErrUserNotFound)
+func (e *Error) Is(target error) bool {
+	var handler ErrorHandler
+	if !errors.As(target, &handler) {
+		return false
+	}
+	return handler.GetCode() == e.Code
+}
+
+// Returns frames of stack trace captured when the Error was created. Returns nil if stack was not captured
+func (e *Error) StackTrace() []runtime.Frame {
+	if len(e.stack) == 0 {
+		return nil
+	}
+	var result []runtime.Frame
+	frames := runtime.CallersFrames(e.stack)
+	for {
+		frame, more := frames.Next()
+		result = append(result, frame)
+		if !more {
+			break
+		}
+	}
+	return result
+}
+
+// Format implements fmt.Formatter. "%v", "%s" and "%q" print message, "%+v" prints code, message,
+// stack trace and chain of causes
+func (e *Error) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			io.WriteString(s, "code "+strconv.Itoa(e.Code)+": "+e.Message)
+			for _, frame := range e.StackTrace() {
+				fmt.Fprintf(s, "\n\t%s\n\t\t%s:%d", frame.Function, frame.File, frame.Line)
+			}
+			if e.cause != nil {
+				fmt.Fprintf(s, "\ncaused by: %+v", e.cause)
+			}
+			return
+		}
+		io.WriteString(s, e.Error())
+	case 's':
+		io.WriteString(s, e.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", e.Error())
+	}
+}
+
+// Program counters of the caller of New or Wrap
+func callers() []uintptr {
+	pcs := make([]uintptr, maxStackDepth)
+	n := runtime.Callers(4, pcs)
+	return pcs[:n]
+}
@@ -0,0 +1,68 @@
+package tiny_errors
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrap(t *testing.T) {
+	err := Wrap(sql.ErrNoRows, 2000, Message("user not found"))
+
+	assert.True(t, errors.Is(err, sql.ErrNoRows))
+	assert.True(t, errors.Is(err, New(2000)))
+	assert.False(t, errors.Is(err, New(2001)))
+	assert.Equal(t, "user not found", err.Error())
+	assert.Equal(t, "user not found", err.GetMessage())
+
+	var target *Error
+	wrapped := fmt.Errorf("service: %w", err)
+	assert.True(t, errors.As(wrapped, &target))
+	assert.Equal(t, 2000, target.GetCode())
+
+	cause := New(1, Message("internal error"), WithCause(errors.New("connection refused")))
+	assert.Equal(t, "internal error", cause.Error())
+	assert.EqualError(t, errors.Unwrap(cause), "connection refused")
+}
+
+func TestWrapIsNotSerialized(t *testing.T) {
+	err := Wrap(errors.New("pq: password authentication failed"), 1, Message("internal error"), WithStack())
+
+	assert.Equal(t, `{"code":1,"message":"internal error","details":null}`, err.JSON())
+	assert.Equal(t, `{"code":1,"message":"internal error","details":null}`, err.JSONOrigin())
+}
+
+func TestStackTrace(t *testing.T) {
+	assert.Nil(t, New(1).(*Error).StackTrace())
+
+	err := New(1, WithStack()).(*Error)
+	frames := err.StackTrace()
+	if assert.NotEmpty(t, frames) {
+		assert.True(t, strings.HasSuffix(frames[0].Function, "TestStackTrace"), frames[0].Function)
+	}
+
+	CaptureStack(true)
+	t.Cleanup(func() { CaptureStack(false) })
+	frames = Wrap(sql.ErrNoRows, 1).(*Error).StackTrace()
+	if assert.NotEmpty(t, frames) {
+		assert.True(t, strings.HasSuffix(frames[0].Function, "TestStackTrace"), frames[0].Function)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	inner := Wrap(sql.ErrNoRows, 1, Message("not found"))
+	err := Wrap(inner, 2, Message("unable to get user"))
+
+	assert.Equal(t, "unable to get user", fmt.Sprintf("%v", err))
+	assert.Equal(t, "unable to get user", fmt.Sprintf("%s", err))
+	assert.Equal(t, `"unable to get user"`, fmt.Sprintf("%q", err))
+	assert.Equal(t, "code 2: unable to get user\ncaused by: code 1: not found\ncaused by: sql: no rows in result set", fmt.Sprintf("%+v", err))
+
+	withStack := fmt.Sprintf("%+v", New(3, Message("with stack"), WithStack()))
+	assert.True(t, strings.HasPrefix(withStack, "code 3: with stack\n\t"), withStack)
+	assert.Contains(t, withStack, "TestFormat")
+}
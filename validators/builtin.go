@@ -0,0 +1,173 @@
+package validators
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Moranilt/http-utils/tiny_errors"
+)
+
+func init() {
+	RegisterRule("required", noParams(Required))
+	RegisterRule("min", numberParam(Min))
+	RegisterRule("max", numberParam(Max))
+	RegisterRule("len", numberParam(Len))
+	RegisterRule("eq", func(params []string) (Rule, error) {
+		return Eq(strings.Join(params, " ")), nil
+	})
+	RegisterRule("ne", func(params []string) (Rule, error) {
+		return Ne(strings.Join(params, " ")), nil
+	})
+	RegisterRule("oneof", func(params []string) (Rule, error) {
+		if len(params) == 0 {
+			return nil, fmt.Errorf("%w: list of values is empty", ErrNotValidParam)
+		}
+		return OneOf(params...), nil
+	})
+	RegisterRule("date", noParams(Date))
+	RegisterRule("datetime", noParams(DateTime))
+	RegisterRule("int", noParams(Int))
+	RegisterRule("url", noParams(URL))
+	RegisterRule("uuid", noParams(UUID))
+}
+
+func noParams(rule func() Rule) RuleFactory {
+	return func(params []string) (Rule, error) {
+		if len(params) > 0 {
+			return nil, fmt.Errorf("%w: rule has no params", ErrNotValidParam)
+		}
+		return rule(), nil
+	}
+}
+
+func numberParam(rule func(float64) Rule) RuleFactory {
+	return func(params []string) (Rule, error) {
+		if len(params) != 1 {
+			return nil, fmt.Errorf("%w: rule has one param", ErrNotValidParam)
+		}
+		number, err := strconv.ParseFloat(params[0], 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q is not a number", ErrNotValidParam, params[0])
+		}
+		return rule(number), nil
+	}
+}
+
+// Value should not be nil, zero or empty
+func Required() Rule {
+	return RuleFunc(func(field Field) []tiny_errors.FieldError {
+		if isEmpty(field.Value) {
+			return []tiny_errors.FieldError{field.Violation("required", "is required", nil)}
+		}
+		return nil
+	})
+}
+
+// Size of value: value of number, count of characters of string or length of slice, array and map
+func size(value reflect.Value) (float64, string, bool) {
+	switch {
+	case value.CanInt():
+		return float64(value.Int()), "", true
+	case value.CanUint():
+		return float64(value.Uint()), "", true
+	case value.CanFloat():
+		return value.Float(), "", true
+	}
+	switch value.Kind() {
+	case reflect.String:
+		return float64(utf8.RuneCountInString(value.String())), " characters", true
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return float64(value.Len()), " items", true
+	}
+	return 0, "", false
+}
+
+func sizeRule(name, message string, limit float64, valid func(size float64) bool) Rule {
+	params := map[string]any{name: limit}
+	return RuleFunc(func(field Field) []tiny_errors.FieldError {
+		value, ok := indirect(field.Value)
+		if !ok {
+			return nil
+		}
+		size, unit, ok := size(value)
+		if ok && valid(size) {
+			return nil
+		}
+		return []tiny_errors.FieldError{field.Violation(name, message+unit, params)}
+	})
+}
+
+// Number should be greater than or equal to min. Strings, slices and maps should have at least min characters or items
+func Min(min float64) Rule {
+	return sizeRule("min", "must be at least {min}", min, func(size float64) bool { return size >= min })
+}
+
+// Number should be less than or equal to max. Strings, slices and maps should have at most max characters or items
+func Max(max float64) Rule {
+	return sizeRule("max", "must be at most {max}", max, func(size float64) bool { return size <= max })
+}
+
+// Number should be equal to length. Strings, slices and maps should have exactly length characters or items
+func Len(length float64) Rule {
+	return sizeRule("len", "must have length {len}", length, func(size float64) bool { return size == length })
+}
+
+// Value should be equal to expected. Values are compared by their string representation
+func Eq(expected string) Rule {
+	return Check("eq", "must be equal to {eq}", map[string]any{"eq": expected}, func(value reflect.Value) bool {
+		return fmt.Sprint(value.Interface()) == expected
+	})
+}
+
+// Value should not be equal to unexpected. Values are compared by their string representation
+func Ne(unexpected string) Rule {
+	return Check("ne", "must not be equal to {ne}", map[string]any{"ne": unexpected}, func(value reflect.Value) bool {
+		return fmt.Sprint(value.Interface()) != unexpected
+	})
+}
+
+// Value should be one of values. Values are compared by their string representation
+func OneOf(values ...string) Rule {
+	params := map[string]any{"oneof": strings.Join(values, ", ")}
+	return Check("oneof", "must be one of {oneof}", params, func(value reflect.Value) bool {
+		return slices.Contains(values, fmt.Sprint(value.Interface()))
+	})
+}
+
+// String should match pattern. Empty strings are not checked
+func Regexp(pattern *regexp.Regexp) Rule {
+	params := map[string]any{"pattern": pattern.String()}
+	return Check("regexp", "must match pattern {pattern}", params, func(value reflect.Value) bool {
+		return value.Kind() == reflect.String && (value.Len() == 0 || pattern.MatchString(value.String()))
+	})
+}
+
+// String should be a date in format YYYY-MM-DD, see ValidDate
+func Date() Rule {
+	return Format("date", "must be a date in format YYYY-MM-DD", ValidDate)
+}
+
+// String should be a date time in RFC3339 format, see ValidDateTime
+func DateTime() Rule {
+	return Format("datetime", "must be a date time in RFC3339 format", ValidDateTime)
+}
+
+// String should be an integer, see ValidInt
+func Int() Rule {
+	return Format("int", "must be an integer", ValidInt)
+}
+
+// String should be a URL, see ValidURL
+func URL() Rule {
+	return Format("url", "must be a valid URL", ValidURL)
+}
+
+// String should be a UUID, see ValidUUID
+func UUID() Rule {
+	return Format("uuid", "must be a valid UUID", ValidUUID)
+}
@@ -0,0 +1,500 @@
+package validators
+
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Moranilt/http-utils/tiny_errors"
+)
+
+func init() {
+	RegisterRule("eqfield", fieldParam(EqField))
+	RegisterRule("nefield", fieldParam(NeField))
+	RegisterRule("gtfield", fieldParam(GtField))
+	RegisterRule("gtefield", fieldParam(GteField))
+	RegisterRule("ltfield", fieldParam(LtField))
+	RegisterRule("ltefield", fieldParam(LteField))
+	RegisterRule("required_if", fieldValuesParams(RequiredIf))
+	RegisterRule("required_unless", fieldValuesParams(RequiredUnless))
+	RegisterRule("required_with", fieldsParams(RequiredWith))
+	RegisterRule("required_without", fieldsParams(RequiredWithout))
+	RegisterRule("excluded_if", fieldValuesParams(ExcludedIf))
+	RegisterRule("excluded_unless", fieldValuesParams(ExcludedUnless))
+	RegisterRule("excluded_with", fieldsParams(ExcludedWith))
+	RegisterRule("exclusive", func(params []string) (Rule, error) {
+		if len(params) < 2 {
+			return nil, fmt.Errorf("%w: rule has at least two names of fields", ErrNotValidParam)
+		}
+		return MutuallyExclusive(params...), nil
+	})
+}
+
+func fieldParam(rule func(name string) Rule) RuleFactory {
+	return func(params []string) (Rule, error) {
+		if len(params) != 1 {
+			return nil, fmt.Errorf("%w: rule has one name of field", ErrNotValidParam)
+		}
+		return rule(params[0]), nil
+	}
+}
+
+func fieldsParams(rule func(names ...string) Rule) RuleFactory {
+	return func(params []string) (Rule, error) {
+		if len(params) == 0 {
+			return nil, fmt.Errorf("%w: list of fields is empty", ErrNotValidParam)
+		}
+		return rule(params...), nil
+	}
+}
+
+func fieldValuesParams(rule func(name string, values ...string) Rule) RuleFactory {
+	return func(params []string) (Rule, error) {
+		if len(params) < 2 {
+			return nil, fmt.Errorf("%w: rule has name of field and list of values", ErrNotValidParam)
+		}
+		return rule(params[0], params[1:]...), nil
+	}
+}
+
+type siblingKey struct {
+	t    reflect.Type
+	name string
+}
+
+var siblingCache sync.Map
+
+// Sibling field of struct which contains field. Name is a name of field from json tag or its name in Go.
+// Returns invalid value if sibling is in nil embedded pointer.
+//
+// Panics if field is not a field of struct or struct has no field with name. Names of fields used by
+// built-in rules are checked before validation, when tags are parsed or rules are passed to Var.
+func (f Field) Sibling(name string) reflect.Value {
+	if !f.Parent.IsValid() {
+		panic(fmt.Sprintf("validators: field %q is not a field of struct, use OnField to validate it with sibling %q", f.Path, name))
+	}
+	value, ok := structField(f.Parent, name)
+	if !ok {
+		panic(fmt.Sprintf("validators: %s has no field %q", f.Parent.Type(), name))
+	}
+	return value
+}
+
+// Field of struct by name from json tag or its name in Go. Returns false if struct has no such field
+func structField(value reflect.Value, name string) (reflect.Value, bool) {
+	index, ok := fieldIndex(value.Type(), name)
+	if !ok {
+		return reflect.Value{}, false
+	}
+	field, ok := fieldByIndex(value, index)
+	if !ok {
+		return reflect.Value{}, true
+	}
+	return field, true
+}
+
+func fieldIndex(t reflect.Type, name string) ([]int, bool) {
+	key := siblingKey{t: t, name: name}
+	if cached, ok := siblingCache.Load(key); ok {
+		return cached.([]int), cached.([]int) != nil
+	}
+
+	var index []int
+	for _, field := range reflect.VisibleFields(t) {
+		if !field.IsExported() {
+			continue
+		}
+		jsonName, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if jsonName == name && jsonName != "-" {
+			index = field.Index
+			break
+		}
+		if field.Name == name && index == nil {
+			index = field.Index
+		}
+	}
+	siblingCache.Store(key, index)
+	return index, index != nil
+}
+
+// Create rule which validates field with name of struct value by rules. It allows to use rules
+// which depend on other fields without tags.
+//
+// Example:
+//
+//	err := validators.Var(req,
+//		validators.OnField("end_date", validators.Required(), validators.GtField("start_date")),
+//		validators.OnField("iban", validators.RequiredIf("payment_method", "bank")),
+//	)
+func OnField(name string, rules ...Rule) Rule {
+	return onFieldRule{name: name, rule: And(rules...)}
+}
+
+type onFieldRule struct {
+	name string
+	rule Rule
+}
+
+func (r onFieldRule) Validate(field Field) []tiny_errors.FieldError {
+	value, ok := indirect(field.Value)
+	if !ok || value.Kind() != reflect.Struct {
+		return nil
+	}
+	fieldValue, ok := structField(value, r.name)
+	if !ok {
+		panic(fmt.Sprintf("validators: %s has no field %q", value.Type(), r.name))
+	}
+	return r.rule.Validate(Field{Path: joinPath(field.Path, r.name), Value: fieldValue, Parent: value})
+}
+
+// Rule which uses other fields of struct, see checkFields
+type fieldsRule struct {
+	Rule
+
+	// Fields of struct which contains validated field
+	siblings []string
+
+	// Fields of validated struct
+	fields []string
+}
+
+func withSiblings(rule Rule, names ...string) Rule {
+	return fieldsRule{Rule: rule, siblings: names}
+}
+
+// Check that fields used by rule exist. Parent is type of struct which contains validated value,
+// nil if value is not a field of struct. Value is type of validated value, nil if it is unknown.
+func checkFields(rule Rule, parent, value reflect.Type) error {
+	switch r := rule.(type) {
+	case andRule:
+		return checkAllFields(r, parent, value)
+	case orRule:
+		return checkAllFields(r, parent, value)
+	case notRule:
+		return checkFields(r.rule, parent, value)
+	case elementsRule:
+		if elem, ok := r.elementType(indirectType(value)); ok {
+			return checkFields(r.elements, parent, elem)
+		}
+	case onFieldRule:
+		t, err := structType(value, r.name)
+		if err != nil || t == nil {
+			return err
+		}
+		index, _ := fieldIndex(t, r.name)
+		return checkFields(r.rule, t, t.FieldByIndex(index).Type)
+	case fieldsRule:
+		for _, name := range r.siblings {
+			if parent == nil {
+				return fmt.Errorf("%w: %q is used on value which is not a field of struct, use OnField", ErrUnknownField, name)
+			}
+			if _, ok := fieldIndex(parent, name); !ok {
+				return fmt.Errorf("%w: %s has no field %q", ErrUnknownField, parent, name)
+			}
+		}
+		for _, name := range r.fields {
+			if _, err := structType(value, name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func checkAllFields(rules []Rule, parent, value reflect.Type) error {
+	for _, rule := range rules {
+		if err := checkFields(rule, parent, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Struct type of value which should have field with name. Returns nil if type is unknown, e.g. interface
+func structType(value reflect.Type, name string) (reflect.Type, error) {
+	t := indirectType(value)
+	if t == nil || t.Kind() == reflect.Interface {
+		return nil, nil
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w: %q is used on %s which is not a struct", ErrUnknownField, name, t)
+	}
+	if _, ok := fieldIndex(t, name); !ok {
+		return nil, fmt.Errorf("%w: %s has no field %q", ErrUnknownField, t, name)
+	}
+	return t, nil
+}
+
+func indirectType(t reflect.Type) reflect.Type {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
+
+var timeType = reflect.TypeFor[time.Time]()
+
+// Time of value of time.Time or string in format of ValidDateTime or ValidDate
+func timeValue(value reflect.Value) (time.Time, bool) {
+	if value.Type() == timeType {
+		return value.Interface().(time.Time), true
+	}
+	if value.Kind() != reflect.String {
+		return time.Time{}, false
+	}
+	if t, err := time.Parse(time.RFC3339, value.String()); err == nil {
+		return t, true
+	}
+	if t, err := time.Parse(time.DateOnly, value.String()); err == nil {
+		return t, true
+	}
+	return time.Time{}, false
+}
+
+func numberValue(value reflect.Value) (float64, bool) {
+	switch {
+	case value.CanInt():
+		return float64(value.Int()), true
+	case value.CanUint():
+		return float64(value.Uint()), true
+	case value.CanFloat():
+		return value.Float(), true
+	}
+	return 0, false
+}
+
+// Compare values of times, date strings, numbers or strings. Returns false if values can not be compared
+func compareValues(a, b reflect.Value) (int, bool) {
+	a, aok := indirect(a)
+	b, bok := indirect(b)
+	if !aok || !bok {
+		return 0, false
+	}
+	if at, ok := timeValue(a); ok {
+		if bt, ok := timeValue(b); ok {
+			return at.Compare(bt), true
+		}
+	}
+	if an, ok := numberValue(a); ok {
+		if bn, ok := numberValue(b); ok {
+			return cmp.Compare(an, bn), true
+		}
+	}
+	if a.Kind() == reflect.String && b.Kind() == reflect.String {
+		return strings.Compare(a.String(), b.String()), true
+	}
+	return 0, false
+}
+
+func equalValues(a, b reflect.Value) bool {
+	if result, ok := compareValues(a, b); ok {
+		return result == 0
+	}
+	a, aok := indirect(a)
+	b, bok := indirect(b)
+	if !aok || !bok {
+		return aok == bok
+	}
+	return reflect.DeepEqual(a.Interface(), b.Interface())
+}
+
+// Value should be equal to value of sibling field. Times and date strings are compared as times
+func EqField(name string) Rule {
+	params := map[string]any{"eqfield": name}
+	return withSiblings(RuleFunc(func(field Field) []tiny_errors.FieldError {
+		if equalValues(field.Value, field.Sibling(name)) {
+			return nil
+		}
+		return []tiny_errors.FieldError{field.Violation("eqfield", "must be equal to {eqfield}", params)}
+	}), name)
+}
+
+// Value should not be equal to value of sibling field. Times and date strings are compared as times
+func NeField(name string) Rule {
+	params := map[string]any{"nefield": name}
+	return withSiblings(RuleFunc(func(field Field) []tiny_errors.FieldError {
+		if !equalValues(field.Value, field.Sibling(name)) {
+			return nil
+		}
+		return []tiny_errors.FieldError{field.Violation("nefield", "must not be equal to {nefield}", params)}
+	}), name)
+}
+
+// Rule which compares value with value of sibling field. Empty values are not checked,
+// values which can not be compared are not valid
+func compareRule(rule, message, name string, valid func(result int) bool) Rule {
+	params := map[string]any{rule: name}
+	return withSiblings(RuleFunc(func(field Field) []tiny_errors.FieldError {
+		other := field.Sibling(name)
+		if isEmpty(field.Value) || isEmpty(other) {
+			return nil
+		}
+		if result, ok := compareValues(field.Value, other); ok && valid(result) {
+			return nil
+		}
+		return []tiny_errors.FieldError{field.Violation(rule, message, params)}
+	}), name)
+}
+
+// Value should be greater than value of sibling field, e.g. later time or date. Empty values are not checked
+func GtField(name string) Rule {
+	return compareRule("gtfield", "must be greater than {gtfield}", name, func(result int) bool { return result > 0 })
+}
+
+// Value should be greater than or equal to value of sibling field. Empty values are not checked
+func GteField(name string) Rule {
+	return compareRule("gtefield", "must be greater than or equal to {gtefield}", name, func(result int) bool { return result >= 0 })
+}
+
+// Value should be less than value of sibling field, e.g. earlier time or date. Empty values are not checked
+func LtField(name string) Rule {
+	return compareRule("ltfield", "must be less than {ltfield}", name, func(result int) bool { return result < 0 })
+}
+
+// Value should be less than or equal to value of sibling field. Empty values are not checked
+func LteField(name string) Rule {
+	return compareRule("ltefield", "must be less than or equal to {ltefield}", name, func(result int) bool { return result <= 0 })
+}
+
+// Value of sibling field is one of values. Values are compared by their string representation
+func siblingIs(field Field, name string, values []string) bool {
+	value, ok := indirect(field.Sibling(name))
+	return ok && slices.Contains(values, fmt.Sprint(value.Interface()))
+}
+
+// Value of at least one of sibling fields is not empty
+func anySiblingSet(field Field, names []string) bool {
+	return slices.ContainsFunc(names, func(name string) bool {
+		return !isEmpty(field.Sibling(name))
+	})
+}
+
+// Rule which requires value if condition is true
+func requiredWhen(rule, message string, params map[string]any, condition func(field Field) bool) Rule {
+	return RuleFunc(func(field Field) []tiny_errors.FieldError {
+		if isEmpty(field.Value) && condition(field) {
+			return []tiny_errors.FieldError{field.Violation(rule, message, params)}
+		}
+		return nil
+	})
+}
+
+// Rule which requires empty value if condition is true
+func excludedWhen(rule, message string, params map[string]any, condition func(field Field) bool) Rule {
+	return RuleFunc(func(field Field) []tiny_errors.FieldError {
+		if !isEmpty(field.Value) && condition(field) {
+			return []tiny_errors.FieldError{field.Violation(rule, message, params)}
+		}
+		return nil
+	})
+}
+
+// Value is required if value of sibling field is one of values
+//
+// Example:
+//
+//	type PaymentRequest struct {
+//		Method string `json:"method" validate:"oneof=bank card"`
+//		IBAN   string `json:"iban" validate:"required_if=method bank,iban"`
+//	}
+func RequiredIf(name string, values ...string) Rule {
+	params := map[string]any{"required_if": name, "values": strings.Join(values, ", ")}
+	return withSiblings(requiredWhen("required_if", "is required when {required_if} is {values}", params, func(field Field) bool {
+		return siblingIs(field, name, values)
+	}), name)
+}
+
+// Value is required if value of sibling field is not one of values
+func RequiredUnless(name string, values ...string) Rule {
+	params := map[string]any{"required_unless": name, "values": strings.Join(values, ", ")}
+	return withSiblings(requiredWhen("required_unless", "is required unless {required_unless} is {values}", params, func(field Field) bool {
+		return !siblingIs(field, name, values)
+	}), name)
+}
+
+// Value is required if at least one of sibling fields is not empty
+func RequiredWith(names ...string) Rule {
+	params := map[string]any{"required_with": strings.Join(names, ", ")}
+	return withSiblings(requiredWhen("required_with", "is required when {required_with} is set", params, func(field Field) bool {
+		return anySiblingSet(field, names)
+	}), names...)
+}
+
+// Value is required if at least one of sibling fields is empty
+func RequiredWithout(names ...string) Rule {
+	params := map[string]any{"required_without": strings.Join(names, ", ")}
+	return withSiblings(requiredWhen("required_without", "is required when {required_without} is not set", params, func(field Field) bool {
+		return slices.ContainsFunc(names, func(name string) bool {
+			return isEmpty(field.Sibling(name))
+		})
+	}), names...)
+}
+
+// Value should be empty if value of sibling field is one of values
+func ExcludedIf(name string, values ...string) Rule {
+	params := map[string]any{"excluded_if": name, "values": strings.Join(values, ", ")}
+	return withSiblings(excludedWhen("excluded_if", "must be empty when {excluded_if} is {values}", params, func(field Field) bool {
+		return siblingIs(field, name, values)
+	}), name)
+}
+
+// Value should be empty if value of sibling field is not one of values
+func ExcludedUnless(name string, values ...string) Rule {
+	params := map[string]any{"excluded_unless": name, "values": strings.Join(values, ", ")}
+	return withSiblings(excludedWhen("excluded_unless", "must be empty unless {excluded_unless} is {values}", params, func(field Field) bool {
+		return !siblingIs(field, name, values)
+	}), name)
+}
+
+// Value should be empty if at least one of sibling fields is not empty
+func ExcludedWith(names ...string) Rule {
+	params := map[string]any{"excluded_with": strings.Join(names, ", ")}
+	return withSiblings(excludedWhen("excluded_with", "must be empty when {excluded_with} is set", params, func(field Field) bool {
+		return anySiblingSet(field, names)
+	}), names...)
+}
+
+// At most one of fields of struct value can be set. Every set field of group gets a violation if
+// more than one is set. Rule is used on struct: in tag of nested struct field, in tag of blank
+// field "_" of struct itself or with Var.
+//
+// Example:
+//
+//	type ContactRequest struct {
+//		_     struct{} `validate:"exclusive=email phone"`
+//		Email string   `json:"email"`
+//		Phone string   `json:"phone"`
+//	}
+//
+//	err := validators.Var(req, validators.MutuallyExclusive("email", "phone", "username"))
+func MutuallyExclusive(names ...string) Rule {
+	params := map[string]any{"exclusive": strings.Join(names, ", ")}
+	return fieldsRule{fields: names, Rule: RuleFunc(func(field Field) []tiny_errors.FieldError {
+		value, ok := indirect(field.Value)
+		if !ok || value.Kind() != reflect.Struct {
+			return nil
+		}
+		var set []string
+		for _, name := range names {
+			fieldValue, ok := structField(value, name)
+			if !ok {
+				panic(fmt.Sprintf("validators: %s has no field %q", value.Type(), name))
+			}
+			if !isEmpty(fieldValue) {
+				set = append(set, name)
+			}
+		}
+		if len(set) < 2 {
+			return nil
+		}
+		violations := make([]tiny_errors.FieldError, len(set))
+		for i, name := range set {
+			violations[i] = Field{Path: joinPath(field.Path, name)}.Violation("exclusive", "only one of {exclusive} can be set", params)
+		}
+		return violations
+	})}
+}
@@ -0,0 +1,225 @@
+package validators
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testContacts struct {
+	Email    string `json:"email"`
+	Phone    string `json:"phone"`
+	Username string `json:"username"`
+}
+
+type testBooking struct {
+	Password        string        `json:"password" validate:"required"`
+	PasswordConfirm string        `json:"password_confirm" validate:"eqfield=password"`
+	StartDate       string        `json:"start_date" validate:"date"`
+	EndDate         string        `json:"end_date" validate:"date,gtfield=start_date"`
+	CheckIn         time.Time     `json:"check_in" validate:"ltfield=CheckOut"`
+	CheckOut        *time.Time    `json:"check_out" validate:"gtefield=start_date"`
+	PaymentMethod   string        `json:"payment_method" validate:"oneof=bank card cash"`
+	IBAN            string        `json:"iban" validate:"required_if=payment_method bank,excluded_unless=payment_method bank"`
+	CardNumber      string        `json:"card_number" validate:"required_if=payment_method card,excluded_with=iban"`
+	Street          string        `json:"street"`
+	City            string        `json:"city" validate:"required_with=street"`
+	Contacts        *testContacts `json:"contacts" validate:"exclusive=email phone username"`
+}
+
+func TestCrossFieldRules(t *testing.T) {
+	checkOut := time.Date(2024, 5, 3, 11, 0, 0, 0, time.UTC)
+	booking := testBooking{
+		Password:        "secret",
+		PasswordConfirm: "secret",
+		StartDate:       "2024-05-01",
+		EndDate:         "2024-05-03",
+		CheckIn:         time.Date(2024, 5, 1, 15, 0, 0, 0, time.UTC),
+		CheckOut:        &checkOut,
+		PaymentMethod:   "bank",
+		IBAN:            "DE89370400440532013000",
+		Contacts:        &testContacts{Email: "john@example.com"},
+	}
+	assert.Nil(t, Violations(booking))
+
+	invalid := booking
+	invalid.PasswordConfirm = "secret1"
+	invalid.EndDate = "2024-04-30"
+	invalid.CheckIn = checkOut.Add(time.Hour)
+	invalid.PaymentMethod = "card"
+	invalid.Street = "Main street"
+	invalid.Contacts = &testContacts{Email: "john@example.com", Username: "john"}
+	assert.Equal(t, []string{
+		"password_confirm:eqfield",
+		"end_date:gtfield",
+		"check_in:ltfield",
+		"iban:excluded_unless",
+		"card_number:required_if",
+		"city:required_with",
+		"contacts.email:exclusive",
+		"contacts.username:exclusive",
+	}, rules(Violations(invalid)))
+
+	invalid = booking
+	invalid.IBAN = ""
+	invalid.CardNumber = "4111111111111111"
+	assert.Equal(t, []string{"iban:required_if"}, rules(Violations(invalid)))
+
+	invalid = booking
+	invalid.PaymentMethod = "cash"
+	invalid.CardNumber = "4111111111111111"
+	assert.Equal(t, []string{"iban:excluded_unless", "card_number:excluded_with"}, rules(Violations(invalid)))
+}
+
+func TestCompareFields(t *testing.T) {
+	type request struct {
+		From     any `json:"from"`
+		To       any `json:"to"`
+		Disabled bool
+	}
+
+	day := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		from  any
+		to    any
+		rule  Rule
+		valid bool
+	}{
+		{"dates", "2024-05-01", "2024-05-02", GtField("from"), true},
+		{"same dates", "2024-05-01", "2024-05-01", GtField("from"), false},
+		{"date and date time", "2024-05-01", "2024-05-01T10:00:00+02:00", GtField("from"), true},
+		{"time and date", day, "2024-05-01", GteField("from"), true},
+		{"time and date time", day.Add(time.Hour), "2024-05-01T00:30:00Z", LtField("from"), true},
+		{"date times with zones", "2024-05-01T10:00:00+02:00", "2024-05-01T08:00:00Z", EqField("from"), true},
+		{"numbers", 10, 9.5, LteField("from"), true},
+		{"numbers greater", 10, uint(11), LteField("from"), false},
+		{"strings", "abc", "abd", GtField("from"), true},
+		{"not comparable", "abc", 10, GtField("from"), false},
+		{"empty", "", "2024-05-01", GtField("from"), true},
+		{"not equal", "a", "b", NeField("from"), true},
+		{"equal slices", []string{"a"}, []string{"a"}, EqField("from"), true},
+		{"equal to nil", nil, "", EqField("from"), false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := Var(request{From: test.from, To: test.to}, OnField("to", test.rule))
+			assert.Equal(t, test.valid, err == nil, err)
+		})
+	}
+}
+
+func TestConditionalRules(t *testing.T) {
+	type request struct {
+		Method string `json:"method"`
+		Notify *bool  `json:"notify"`
+		Email  string `json:"email"`
+		Phone  string `json:"phone"`
+	}
+
+	yes := true
+	tests := []struct {
+		name       string
+		req        request
+		rule       Rule
+		violations []string
+	}{
+		{"required if", request{Method: "email"}, OnField("email", RequiredIf("method", "email", "sms")), []string{"email:required_if"}},
+		{"required if bool", request{Notify: &yes}, OnField("email", RequiredIf("notify", "true")), []string{"email:required_if"}},
+		{"required if other value", request{Method: "push"}, OnField("email", RequiredIf("method", "email")), nil},
+		{"required unless", request{Method: "push"}, OnField("email", RequiredUnless("method", "sms")), []string{"email:required_unless"}},
+		{"required without", request{}, OnField("email", RequiredWithout("phone")), []string{"email:required_without"}},
+		{"required without set", request{Phone: "+4915112345678"}, OnField("email", RequiredWithout("phone")), nil},
+		{"excluded if", request{Method: "sms", Email: "a@b.c"}, OnField("email", ExcludedIf("method", "sms")), []string{"email:excluded_if"}},
+		{"exclusive", request{Email: "a@b.c", Phone: "+4915112345678"}, MutuallyExclusive("email", "phone"), []string{"email:exclusive", "phone:exclusive"}},
+		{"exclusive one", request{Phone: "+4915112345678"}, MutuallyExclusive("email", "phone"), nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.violations, rules(And(test.rule).Validate(Field{Value: reflect.ValueOf(test.req)})))
+		})
+	}
+}
+
+func TestCrossFieldMessages(t *testing.T) {
+	err := Var(struct {
+		Method string `json:"method"`
+		IBAN   string `json:"iban"`
+	}{Method: "bank"}, OnField("iban", RequiredIf("method", "bank", "sepa")))
+
+	assert.Equal(t, "validation failed: iban is required when method is bank, sepa", err.Error())
+}
+
+func TestCrossFieldTagErrors(t *testing.T) {
+	for _, tag := range []string{"eqfield", "eqfield=a b", "required_if=method", "required_with", "exclusive=email"} {
+		_, err := ParseTag(tag)
+		assert.True(t, errors.Is(err, ErrNotValidParam), tag)
+	}
+}
+
+func TestSiblingPanics(t *testing.T) {
+	assert.Panics(t, func() {
+		Var("value", EqField("password"))
+	})
+	assert.Panics(t, func() {
+		Var(struct{ Name string }{}, OnField("name", EqField("unknown")))
+	})
+	assert.Panics(t, func() {
+		Var(struct{ Name string }{}, MutuallyExclusive("name", "unknown"))
+	})
+}
+
+func TestUnknownFieldInTag(t *testing.T) {
+	type request struct {
+		Method string `json:"method"`
+		IBAN   string `json:"iban" validate:"required_if=methd bank"`
+	}
+
+	err := Compile(request{})
+	assert.ErrorIs(t, err, ErrUnknownField)
+	assert.ErrorContains(t, err, `"methd"`)
+
+	// Panics for every payload, not only when rule reads the sibling
+	assert.Panics(t, func() { Struct(request{IBAN: "DE89370400440532013000"}) })
+	assert.Panics(t, func() { Struct(request{}) })
+
+	type nested struct {
+		Items []struct {
+			Amount int `json:"amount" validate:"ltefield=limit"`
+		} `json:"items"`
+	}
+	assert.ErrorIs(t, Compile(&nested{}), ErrUnknownField)
+
+	type exclusive struct {
+		Contacts testContacts `json:"contacts" validate:"exclusive=email fax"`
+	}
+	assert.ErrorIs(t, Compile(exclusive{}), ErrUnknownField)
+
+	assert.NoError(t, Compile(testBooking{}))
+}
+
+func TestExclusiveOnStruct(t *testing.T) {
+	type request struct {
+		_     struct{} `validate:"exclusive=email phone"`
+		Email string   `json:"email" validate:"omitempty,email"`
+		Phone string   `json:"phone"`
+	}
+
+	assert.NoError(t, Compile(request{}))
+	assert.Nil(t, Struct(request{Email: "a@b.c"}))
+	assert.Equal(t, []string{"email:exclusive", "phone:exclusive"}, rules(Violations(request{Email: "a@b.c", Phone: "+4915112345678"})))
+	assert.Equal(t, []string{"owner.email:exclusive", "owner.phone:exclusive"}, rules(Violations(struct {
+		Owner request `json:"owner"`
+	}{Owner: request{Email: "a@b.c", Phone: "+4915112345678"}})))
+
+	type unknown struct {
+		_     struct{} `validate:"exclusive=email fax"`
+		Email string   `json:"email"`
+	}
+	assert.ErrorIs(t, Compile(unknown{}), ErrUnknownField)
+}
@@ -0,0 +1,397 @@
+package validators
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/mail"
+	"net/netip"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func init() {
+	formats := []struct {
+		name      string
+		message   string
+		normalize func(value string) (string, bool)
+	}{
+		{"email", "must be a valid email", NormalizeEmail},
+		{"e164", "must be a phone number in E.164 format", NormalizePhone},
+		{"iban", "must be a valid IBAN", NormalizeIBAN},
+		{"card", "must be a valid card number", NormalizeCardNumber},
+		{"ip", "must be a valid IP address", NormalizeIP},
+		{"ipv4", "must be a valid IPv4 address", NormalizeIPv4},
+		{"ipv6", "must be a valid IPv6 address", NormalizeIPv6},
+		{"cidr", "must be a valid CIDR", NormalizeCIDR},
+		{"hostname", "must be a valid hostname", NormalizeHostname},
+		{"semver", "must be a semantic version", NormalizeSemver},
+		{"country", "must be an ISO 3166-1 alpha-2 country code", NormalizeCountryCode},
+		{"currency", "must be an ISO 4217 currency code", NormalizeCurrencyCode},
+		{"base64", "must be base64 encoded", NormalizeBase64},
+		{"hexcolor", "must be a hex color", NormalizeHexColor},
+		{"json", "must be valid JSON", NormalizeJSON},
+		{"timezone", "must be a valid time zone", NormalizeTimeZone},
+	}
+	for _, format := range formats {
+		RegisterFormat(format.name, format.message, valid(format.normalize))
+	}
+}
+
+func valid(normalize func(value string) (string, bool)) func(value string) bool {
+	return func(value string) bool {
+		_, ok := normalize(value)
+		return ok
+	}
+}
+
+// NormalizeEmail checks if the string is an email address without display name
+// and returns it trimmed and lower-cased
+func NormalizeEmail(value string) (string, bool) {
+	value = strings.TrimSpace(value)
+	address, err := mail.ParseAddress(value)
+	if err != nil || address.Name != "" || address.Address != value {
+		return "", false
+	}
+	local, domain, ok := strings.Cut(value, "@")
+	if !ok || local == "" {
+		return "", false
+	}
+	if _, ok := NormalizeHostname(domain); !ok || !strings.Contains(domain, ".") {
+		return "", false
+	}
+	return strings.ToLower(value), true
+}
+
+// ValidEmail checks if the string is an email address, see NormalizeEmail
+func ValidEmail(value string) bool {
+	_, ok := NormalizeEmail(value)
+	return ok
+}
+
+// NormalizePhone checks if the string is an international phone number and returns it in E.164 format, e.g. "+4915112345678".
+// Spaces, dashes, dots and parentheses are removed, prefix "00" is replaced with "+".
+func NormalizePhone(value string) (string, bool) {
+	value = strings.TrimSpace(value)
+	if strings.HasPrefix(value, "00") {
+		value = "+" + value[2:]
+	}
+	if !strings.HasPrefix(value, "+") {
+		return "", false
+	}
+
+	var digits strings.Builder
+	digits.WriteByte('+')
+	for _, r := range value[1:] {
+		switch {
+		case r >= '0' && r <= '9':
+			digits.WriteRune(r)
+		case r == ' ' || r == '-' || r == '.' || r == '(' || r == ')':
+		default:
+			return "", false
+		}
+	}
+	phone := digits.String()
+	if len(phone) < 8 || len(phone) > 16 || phone[1] == '0' {
+		return "", false
+	}
+	return phone, true
+}
+
+// ValidPhone checks if the string is an international phone number, see NormalizePhone
+func ValidPhone(value string) bool {
+	_, ok := NormalizePhone(value)
+	return ok
+}
+
+// NormalizeIBAN checks length and check digits of IBAN and returns it without spaces in upper case
+func NormalizeIBAN(value string) (string, bool) {
+	iban := strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(value), " ", ""))
+	if len(iban) < 15 || len(iban) > 34 {
+		return "", false
+	}
+	if length, ok := ibanLengths[iban[:2]]; !ok || length != len(iban) {
+		return "", false
+	}
+
+	var digits strings.Builder
+	for _, r := range iban[4:] + iban[:4] {
+		switch {
+		case r >= '0' && r <= '9':
+			digits.WriteRune(r)
+		case r >= 'A' && r <= 'Z':
+			digits.WriteString(strconv.Itoa(int(r-'A') + 10))
+		default:
+			return "", false
+		}
+	}
+	number, ok := new(big.Int).SetString(digits.String(), 10)
+	if !ok || new(big.Int).Mod(number, big.NewInt(97)).Int64() != 1 {
+		return "", false
+	}
+	return iban, true
+}
+
+// ValidIBAN checks if the string is an IBAN, see NormalizeIBAN
+func ValidIBAN(value string) bool {
+	_, ok := NormalizeIBAN(value)
+	return ok
+}
+
+// NormalizeCardNumber checks length and Luhn checksum of payment card number and returns digits only.
+// Spaces and dashes are removed.
+func NormalizeCardNumber(value string) (string, bool) {
+	var digits []byte
+	for _, r := range strings.TrimSpace(value) {
+		switch {
+		case r >= '0' && r <= '9':
+			digits = append(digits, byte(r))
+		case r == ' ' || r == '-':
+		default:
+			return "", false
+		}
+	}
+	if len(digits) < 12 || len(digits) > 19 {
+		return "", false
+	}
+
+	var sum int
+	for i := range digits {
+		digit := int(digits[len(digits)-1-i] - '0')
+		if i%2 == 1 {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+	}
+	if sum%10 != 0 {
+		return "", false
+	}
+	return string(digits), true
+}
+
+// ValidCardNumber checks if the string is a payment card number, see NormalizeCardNumber
+func ValidCardNumber(value string) bool {
+	_, ok := NormalizeCardNumber(value)
+	return ok
+}
+
+// NormalizeIP checks if the string is an IPv4 or IPv6 address and returns it in canonical form,
+// e.g. "2001:db8::1" for "2001:0DB8:0000::0001"
+func NormalizeIP(value string) (string, bool) {
+	addr, err := netip.ParseAddr(strings.TrimSpace(value))
+	if err != nil || addr.Zone() != "" {
+		return "", false
+	}
+	return addr.String(), true
+}
+
+// NormalizeIPv4 checks if the string is an IPv4 address and returns it in canonical form
+func NormalizeIPv4(value string) (string, bool) {
+	addr, err := netip.ParseAddr(strings.TrimSpace(value))
+	if err != nil || !addr.Is4() {
+		return "", false
+	}
+	return addr.String(), true
+}
+
+// NormalizeIPv6 checks if the string is an IPv6 address and returns it in canonical form
+func NormalizeIPv6(value string) (string, bool) {
+	addr, err := netip.ParseAddr(strings.TrimSpace(value))
+	if err != nil || !addr.Is6() || addr.Zone() != "" {
+		return "", false
+	}
+	return addr.String(), true
+}
+
+// ValidIP checks if the string is an IPv4 or IPv6 address
+func ValidIP(value string) bool {
+	_, ok := NormalizeIP(value)
+	return ok
+}
+
+// NormalizeCIDR checks if the string is an IP prefix and returns it in canonical form, e.g. "2001:db8::/32".
+// Address is not masked, so "10.0.0.1/8" stays as is.
+func NormalizeCIDR(value string) (string, bool) {
+	prefix, err := netip.ParsePrefix(strings.TrimSpace(value))
+	if err != nil {
+		return "", false
+	}
+	return prefix.String(), true
+}
+
+// ValidCIDR checks if the string is an IP prefix, see NormalizeCIDR
+func ValidCIDR(value string) bool {
+	_, ok := NormalizeCIDR(value)
+	return ok
+}
+
+// NormalizeHostname checks if the string is a hostname of RFC 1123 and returns it in lower case without trailing dot
+func NormalizeHostname(value string) (string, bool) {
+	hostname := strings.TrimSuffix(strings.ToLower(strings.TrimSpace(value)), ".")
+	if hostname == "" || len(hostname) > 253 {
+		return "", false
+	}
+	for _, label := range strings.Split(hostname, ".") {
+		if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return "", false
+		}
+		for _, r := range label {
+			if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' {
+				return "", false
+			}
+		}
+	}
+	return hostname, true
+}
+
+// ValidHostname checks if the string is a hostname, see NormalizeHostname
+func ValidHostname(value string) bool {
+	_, ok := NormalizeHostname(value)
+	return ok
+}
+
+var semverPattern = regexp.MustCompile(`^(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)` +
+	`(?:-((?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?` +
+	`(?:\+([0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?$`)
+
+// NormalizeSemver checks if the string is a semantic version 2.0.0 and returns it without prefix "v"
+func NormalizeSemver(value string) (string, bool) {
+	version := strings.TrimPrefix(strings.TrimSpace(value), "v")
+	if !semverPattern.MatchString(version) {
+		return "", false
+	}
+	return version, true
+}
+
+// ValidSemver checks if the string is a semantic version, see NormalizeSemver
+func ValidSemver(value string) bool {
+	_, ok := NormalizeSemver(value)
+	return ok
+}
+
+// NormalizeCountryCode checks if the string is an ISO 3166-1 alpha-2 country code and returns it in upper case
+func NormalizeCountryCode(value string) (string, bool) {
+	code := strings.ToUpper(strings.TrimSpace(value))
+	if _, ok := countryCodes[code]; !ok {
+		return "", false
+	}
+	return code, true
+}
+
+// ValidCountryCode checks if the string is a country code, see NormalizeCountryCode
+func ValidCountryCode(value string) bool {
+	_, ok := NormalizeCountryCode(value)
+	return ok
+}
+
+// NormalizeCurrencyCode checks if the string is an ISO 4217 currency code and returns it in upper case
+func NormalizeCurrencyCode(value string) (string, bool) {
+	code := strings.ToUpper(strings.TrimSpace(value))
+	if _, ok := currencyCodes[code]; !ok {
+		return "", false
+	}
+	return code, true
+}
+
+// ValidCurrencyCode checks if the string is a currency code, see NormalizeCurrencyCode
+func ValidCurrencyCode(value string) bool {
+	_, ok := NormalizeCurrencyCode(value)
+	return ok
+}
+
+// NormalizeBase64 checks if the string is encoded by standard or URL base64 encoding with or without padding
+// and returns it in standard encoding with padding
+func NormalizeBase64(value string) (string, bool) {
+	value = strings.TrimSpace(value)
+	for _, encoding := range []*base64.Encoding{base64.StdEncoding, base64.RawStdEncoding, base64.URLEncoding, base64.RawURLEncoding} {
+		if data, err := encoding.DecodeString(value); err == nil {
+			return base64.StdEncoding.EncodeToString(data), true
+		}
+	}
+	return "", false
+}
+
+// ValidBase64 checks if the string is base64 encoded, see NormalizeBase64
+func ValidBase64(value string) bool {
+	_, ok := NormalizeBase64(value)
+	return ok
+}
+
+var hexColorPattern = regexp.MustCompile(`^#(?:[0-9a-fA-F]{3,4}|[0-9a-fA-F]{6}|[0-9a-fA-F]{8})$`)
+
+// NormalizeHexColor checks if the string is a hex color "#rgb", "#rgba", "#rrggbb" or "#rrggbbaa"
+// and returns it in full form in lower case, e.g. "#ffaa00" for "#FA0"
+func NormalizeHexColor(value string) (string, bool) {
+	value = strings.TrimSpace(value)
+	if !hexColorPattern.MatchString(value) {
+		return "", false
+	}
+	color := strings.ToLower(value[1:])
+	if len(color) <= 4 {
+		var full strings.Builder
+		for _, r := range color {
+			full.WriteRune(r)
+			full.WriteRune(r)
+		}
+		color = full.String()
+	}
+	return "#" + color, true
+}
+
+// ValidHexColor checks if the string is a hex color, see NormalizeHexColor
+func ValidHexColor(value string) bool {
+	_, ok := NormalizeHexColor(value)
+	return ok
+}
+
+// NormalizeJSON checks if the string is valid JSON and returns it without insignificant spaces
+func NormalizeJSON(value string) (string, bool) {
+	var compacted bytes.Buffer
+	if err := json.Compact(&compacted, []byte(value)); err != nil {
+		return "", false
+	}
+	return compacted.String(), true
+}
+
+// ValidJSON checks if the string is valid JSON
+func ValidJSON(value string) bool {
+	return json.Valid([]byte(value))
+}
+
+// NormalizeTimeZone checks if the string is a name of IANA time zone, e.g. "Europe/Berlin", or "UTC".
+// Time zone database of the system or embedded by package time/tzdata is used.
+func NormalizeTimeZone(value string) (string, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" || value == "Local" {
+		return "", false
+	}
+	location, err := time.LoadLocation(value)
+	if err != nil {
+		return "", false
+	}
+	return location.String(), true
+}
+
+// ValidTimeZone checks if the string is a name of time zone, see NormalizeTimeZone
+func ValidTimeZone(value string) bool {
+	_, ok := NormalizeTimeZone(value)
+	return ok
+}
+
+// NormalizeUUID checks if the string is a UUID and returns it in canonical form,
+// e.g. "9aef6831-8538-4b03-8f61-7a687861584a" for "{9AEF6831-8538-4B03-8F61-7A687861584A}"
+func NormalizeUUID(value string) (string, bool) {
+	parsed, err := uuid.Parse(strings.TrimSpace(value))
+	if err != nil {
+		return "", false
+	}
+	return parsed.String(), true
+}
@@ -0,0 +1,192 @@
+package validators
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type normalizeTest struct {
+	arg        string
+	normalized string
+	valid      bool
+}
+
+func runNormalizeTest(t *testing.T, normalize func(value string) (string, bool), tests []normalizeTest) {
+	t.Helper()
+	for _, test := range tests {
+		t.Run(test.arg, func(t *testing.T) {
+			normalized, valid := normalize(test.arg)
+			assert.Equal(t, test.valid, valid)
+			assert.Equal(t, test.normalized, normalized)
+		})
+	}
+}
+
+func TestNormalizeEmail(t *testing.T) {
+	runNormalizeTest(t, NormalizeEmail, []normalizeTest{
+		{" Foo@Example.com ", "foo@example.com", true},
+		{"john.doe+tag@mail.example.org", "john.doe+tag@mail.example.org", true},
+		{"John <john@example.com>", "", false},
+		{"john@localhost", "", false},
+		{"john@-example.com", "", false},
+		{"john.example.com", "", false},
+		{"", "", false},
+	})
+}
+
+func TestNormalizePhone(t *testing.T) {
+	runNormalizeTest(t, NormalizePhone, []normalizeTest{
+		{"+49 151 1234-5678", "+4915112345678", true},
+		{"+1 (415) 555.2671", "+14155552671", true},
+		{"0044 20 7946 0958", "+442079460958", true},
+		{"415 555 2671", "", false},
+		{"+0 123 456 789", "", false},
+		{"+1234567890123456", "", false},
+		{"+49 151 abc", "", false},
+	})
+}
+
+func TestNormalizeIBAN(t *testing.T) {
+	runNormalizeTest(t, NormalizeIBAN, []normalizeTest{
+		{"DE89 3704 0044 0532 0130 00", "DE89370400440532013000", true},
+		{"gb82west12345698765432", "GB82WEST12345698765432", true},
+		{"DE88 3704 0044 0532 0130 00", "", false},
+		{"DE89 3704 0044 0532 0130", "", false},
+		{"XX89370400440532013000", "", false},
+	})
+}
+
+func TestNormalizeCardNumber(t *testing.T) {
+	runNormalizeTest(t, NormalizeCardNumber, []normalizeTest{
+		{"4111 1111 1111 1111", "4111111111111111", true},
+		{"5500-0000-0000-0004", "5500000000000004", true},
+		{"4111 1111 1111 1112", "", false},
+		{"4111", "", false},
+		{"4111-1111-1111-111a", "", false},
+	})
+}
+
+func TestNormalizeIP(t *testing.T) {
+	runNormalizeTest(t, NormalizeIP, []normalizeTest{
+		{"192.168.0.1", "192.168.0.1", true},
+		{"2001:0DB8:0000::0001", "2001:db8::1", true},
+		{"fe80::1%eth0", "", false},
+		{"256.0.0.1", "", false},
+	})
+	runNormalizeTest(t, NormalizeIPv4, []normalizeTest{
+		{"10.0.0.1", "10.0.0.1", true},
+		{"::1", "", false},
+	})
+	runNormalizeTest(t, NormalizeIPv6, []normalizeTest{
+		{"::FFFF:10.0.0.1", "::ffff:10.0.0.1", true},
+		{"10.0.0.1", "", false},
+	})
+}
+
+func TestNormalizeCIDR(t *testing.T) {
+	runNormalizeTest(t, NormalizeCIDR, []normalizeTest{
+		{"10.0.0.0/8", "10.0.0.0/8", true},
+		{"2001:DB8::/32", "2001:db8::/32", true},
+		{"10.0.0.0/33", "", false},
+		{"10.0.0.0", "", false},
+	})
+}
+
+func TestNormalizeHostname(t *testing.T) {
+	runNormalizeTest(t, NormalizeHostname, []normalizeTest{
+		{"API.Example.com.", "api.example.com", true},
+		{"localhost", "localhost", true},
+		{"-example.com", "", false},
+		{"exa_mple.com", "", false},
+		{"example..com", "", false},
+	})
+}
+
+func TestNormalizeSemver(t *testing.T) {
+	runNormalizeTest(t, NormalizeSemver, []normalizeTest{
+		{"1.2.3", "1.2.3", true},
+		{"v1.0.0-rc.1+build.5", "1.0.0-rc.1+build.5", true},
+		{"1.2", "", false},
+		{"01.2.3", "", false},
+	})
+}
+
+func TestNormalizeCodes(t *testing.T) {
+	runNormalizeTest(t, NormalizeCountryCode, []normalizeTest{
+		{"de", "DE", true},
+		{"XX", "", false},
+		{"DEU", "", false},
+	})
+	runNormalizeTest(t, NormalizeCurrencyCode, []normalizeTest{
+		{"eur", "EUR", true},
+		{"ZWG", "ZWG", true},
+		{"xcg", "XCG", true},
+		{"ABC", "", false},
+		{"ZWL", "", false},
+		{"SLL", "", false},
+		{"ANG", "", false},
+	})
+}
+
+func TestNormalizeBase64(t *testing.T) {
+	runNormalizeTest(t, NormalizeBase64, []normalizeTest{
+		{"aGVsbG8=", "aGVsbG8=", true},
+		{"aGVsbG8", "aGVsbG8=", true},
+		{"-_8=", "+/8=", true},
+		{"not base64!", "", false},
+	})
+}
+
+func TestNormalizeHexColor(t *testing.T) {
+	runNormalizeTest(t, NormalizeHexColor, []normalizeTest{
+		{"#FA0", "#ffaa00", true},
+		{"#ffaa0080", "#ffaa0080", true},
+		{"#F0A8", "#ff00aa88", true},
+		{"FFAA00", "", false},
+		{"#GGG", "", false},
+	})
+}
+
+func TestNormalizeJSON(t *testing.T) {
+	runNormalizeTest(t, NormalizeJSON, []normalizeTest{
+		{`{ "a": [1, 2] }`, `{"a":[1,2]}`, true},
+		{`"text"`, `"text"`, true},
+		{`{"a":}`, "", false},
+	})
+	assert.True(t, ValidJSON(`[1]`))
+}
+
+func TestNormalizeTimeZone(t *testing.T) {
+	runNormalizeTest(t, NormalizeTimeZone, []normalizeTest{
+		{"Europe/Berlin", "Europe/Berlin", true},
+		{"UTC", "UTC", true},
+		{"Local", "", false},
+		{"Mars/Olympus", "", false},
+	})
+}
+
+func TestNormalizeUUID(t *testing.T) {
+	runNormalizeTest(t, NormalizeUUID, []normalizeTest{
+		{"{9AEF6831-8538-4B03-8F61-7A687861584A}", "9aef6831-8538-4b03-8f61-7a687861584a", true},
+		{"urn:uuid:9aef6831-8538-4b03-8f61-7a687861584a", "9aef6831-8538-4b03-8f61-7a687861584a", true},
+		{"9aef6831", "", false},
+	})
+}
+
+func TestFormatRules(t *testing.T) {
+	type request struct {
+		Email    string `json:"email" validate:"required,email"`
+		Phone    string `json:"phone" validate:"e164"`
+		Country  string `json:"country" validate:"country"`
+		Currency string `json:"currency" validate:"omitempty,currency"`
+		Color    string `json:"color" validate:"hexcolor"`
+	}
+
+	assert.Equal(t, []string{"email:email", "phone:e164", "color:hexcolor"}, rules(Violations(request{
+		Email:   "not email",
+		Phone:   "123",
+		Country: "DE",
+		Color:   "red",
+	})))
+}
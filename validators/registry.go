@@ -0,0 +1,175 @@
+package validators
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	ErrUnknownRule   = errors.New("unknown validation rule")
+	ErrNotValidRule  = errors.New("not valid validation rule")
+	ErrNotValidParam = errors.New("not valid param of validation rule")
+	ErrUnknownField  = errors.New("unknown field of struct")
+)
+
+// Names of rules which group other rules in tags
+const (
+	GroupEach   = "each"
+	GroupKeys   = "keys"
+	GroupValues = "values"
+	GroupNot    = "not"
+
+	RuleOmitEmpty = "omitempty"
+)
+
+// RuleFactory creates rule with params of tag, e.g. ["3"] for "min=3"
+type RuleFactory func(params []string) (Rule, error)
+
+var (
+	ruleStorage atomic.Value
+	ruleMu      sync.Mutex
+)
+
+func loadRules() map[string]RuleFactory {
+	rules, _ := ruleStorage.Load().(map[string]RuleFactory)
+	return rules
+}
+
+// Register rule which can be used in tags. Rule registered before with the same name is replaced.
+//
+// Example:
+//
+//	validators.RegisterRule("even", func(params []string) (validators.Rule, error) {
+//		return validators.Check("even", "must be even", nil, func(value reflect.Value) bool {
+//			return value.CanInt() && value.Int()%2 == 0
+//		}), nil
+//	})
+func RegisterRule(name string, factory RuleFactory) {
+	ruleMu.Lock()
+	defer ruleMu.Unlock()
+
+	rules := maps.Clone(loadRules())
+	if rules == nil {
+		rules = make(map[string]RuleFactory)
+	}
+	rules[name] = factory
+	ruleStorage.Store(rules)
+	clearTagCache()
+}
+
+// Register rule without params which checks format of strings, see Format
+func RegisterFormat(name, message string, valid func(value string) bool) {
+	rule := Format(name, message, valid)
+	RegisterRule(name, func(params []string) (Rule, error) {
+		return rule, nil
+	})
+}
+
+// Create registered rule by name with params
+func Named(name string, params ...string) (Rule, error) {
+	if name == RuleOmitEmpty {
+		return OmitEmpty(), nil
+	}
+	factory, ok := loadRules()[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownRule, name)
+	}
+	rule, err := factory(params)
+	if err != nil {
+		return nil, fmt.Errorf("rule %q: %w", name, err)
+	}
+	return rule, nil
+}
+
+// Parse rules of tag. Rules are separated by "," (and) or "|" (or). Params follow the name of rule
+// after "=" and are separated by spaces. Groups each(...), keys(...), values(...) and not(...) contain other rules.
+//
+// Example:
+//
+//	rule, err := validators.ParseTag("required,min=1,each(uuid|int)")
+func ParseTag(tag string) (Rule, error) {
+	parts, err := splitTopLevel(tag, ',')
+	if err != nil {
+		return nil, err
+	}
+
+	rules := make([]Rule, 0, len(parts))
+	for _, part := range parts {
+		alternatives, err := splitTopLevel(part, '|')
+		if err != nil {
+			return nil, err
+		}
+		or := make([]Rule, len(alternatives))
+		for i, alternative := range alternatives {
+			if or[i], err = parseRule(strings.TrimSpace(alternative)); err != nil {
+				return nil, err
+			}
+		}
+		rules = append(rules, Or(or...))
+	}
+	return And(rules...), nil
+}
+
+func parseRule(text string) (Rule, error) {
+	if text == "" {
+		return nil, fmt.Errorf("%w: empty rule", ErrNotValidRule)
+	}
+
+	if open := strings.IndexByte(text, '('); open >= 0 {
+		if !strings.HasSuffix(text, ")") {
+			return nil, fmt.Errorf("%w: %q", ErrNotValidRule, text)
+		}
+		name := strings.TrimSpace(text[:open])
+		inner, err := ParseTag(text[open+1 : len(text)-1])
+		if err != nil {
+			return nil, err
+		}
+		switch name {
+		case GroupEach:
+			return Each(inner), nil
+		case GroupKeys:
+			return Keys(inner), nil
+		case GroupValues:
+			return Values(inner), nil
+		case GroupNot:
+			return Not(inner), nil
+		}
+		return nil, fmt.Errorf("%w: unknown group %q", ErrNotValidRule, name)
+	}
+
+	name, params, _ := strings.Cut(text, "=")
+	return Named(strings.TrimSpace(name), strings.Fields(params)...)
+}
+
+// Split text by separator which is not in parentheses
+func splitTopLevel(text string, separator byte) ([]string, error) {
+	var (
+		parts []string
+		depth int
+		start int
+	)
+	for i := 0; i < len(text); i++ {
+		switch text[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth < 0 {
+				return nil, fmt.Errorf("%w: unbalanced parentheses in %q", ErrNotValidRule, text)
+			}
+		case separator:
+			if depth == 0 {
+				parts = append(parts, text[start:i])
+				start = i + 1
+			}
+		}
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("%w: unbalanced parentheses in %q", ErrNotValidRule, text)
+	}
+	return append(parts, text[start:]), nil
+}
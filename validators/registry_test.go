@@ -0,0 +1,91 @@
+package validators
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTag(t *testing.T) {
+	tests := []struct {
+		tag        string
+		value      any
+		violations []string
+	}{
+		{"required,min=3", "", []string{"field:required"}},
+		{"required,min=3", "Jo", []string{"field:min"}},
+		{"omitempty,min=18", 0, nil},
+		{"oneof=bank card", "cash", []string{"field:oneof"}},
+		{"uuid|int", "abc", []string{"field:uuid|int"}},
+		{"max=3,each(required,uuid|int)", []string{"1", "", "x"}, []string{"field[1]:required", "field[2]:uuid|int"}},
+		{"keys(min=2),values(each(required))", map[string][]string{"a": {"x"}, "bc": {""}}, []string{"field.a:min"}},
+		{"not(oneof=admin root)", "root", []string{"field:not"}},
+		{" required , max=5 ", "abcdef", []string{"field:max"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.tag, func(t *testing.T) {
+			rule, err := ParseTag(test.tag)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, test.violations, rules(violations(test.value, rule)))
+		})
+	}
+}
+
+func TestParseTagErrors(t *testing.T) {
+	tests := []struct {
+		tag string
+		err error
+	}{
+		{"required,unknown", ErrUnknownRule},
+		{"min=abc", ErrNotValidParam},
+		{"min", ErrNotValidParam},
+		{"required=1", ErrNotValidParam},
+		{"oneof", ErrNotValidParam},
+		{"each(required", ErrNotValidRule},
+		{"required)", ErrNotValidRule},
+		{"dive(required)", ErrNotValidRule},
+		{"required,,min=1", ErrNotValidRule},
+	}
+
+	for _, test := range tests {
+		t.Run(test.tag, func(t *testing.T) {
+			_, err := ParseTag(test.tag)
+			assert.True(t, errors.Is(err, test.err), err)
+		})
+	}
+}
+
+func TestRegisterRule(t *testing.T) {
+	saved := loadRules()
+	t.Cleanup(func() {
+		ruleStorage.Store(saved)
+		clearTagCache()
+	})
+
+	RegisterRule("divisible", func(params []string) (Rule, error) {
+		rule, err := numberParam(func(divisor float64) Rule {
+			return Check("divisible", "must be divisible by {divisible}", map[string]any{"divisible": divisor}, func(value reflect.Value) bool {
+				return value.CanInt() && value.Int()%int64(divisor) == 0
+			})
+		})(params)
+		return rule, err
+	})
+	RegisterFormat("slug", "must be a slug", func(value string) bool {
+		return value == "valid-slug"
+	})
+
+	rule, err := ParseTag("divisible=3")
+	assert.NoError(t, err)
+	assert.Empty(t, violations(9, rule))
+	assert.Equal(t, "must be divisible by 3", violations(10, rule)[0].Message)
+
+	rule, err = Named("slug")
+	assert.NoError(t, err)
+	assert.Empty(t, violations("valid-slug", rule))
+	assert.Equal(t, []string{"field:slug"}, rules(violations("Not Slug", rule)))
+}
@@ -0,0 +1,317 @@
+package validators
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/Moranilt/http-utils/tiny_errors"
+)
+
+// Field which is validated by rule
+type Field struct {
+	// Path of field, e.g. "items[0].name". Empty for validated value itself
+	Path string
+
+	Value reflect.Value
+
+	// Struct which contains field. Invalid if value is not a field of struct
+	Parent reflect.Value
+}
+
+// Create violation of rule by field. Message may contain params in braces, e.g. "must be at least {min}"
+func (f Field) Violation(rule, message string, params map[string]any) tiny_errors.FieldError {
+	return tiny_errors.FieldError{
+		Field:   f.Path,
+		Rule:    rule,
+		Message: formatMessage(message, params),
+		Params:  params,
+	}
+}
+
+// Child field of value, e.g. element of slice. Parent of child is the same as parent of field
+func (f Field) child(path string, value reflect.Value) Field {
+	return Field{Path: path, Value: value, Parent: f.Parent}
+}
+
+// Replace params in braces with their values
+func formatMessage(message string, params map[string]any) string {
+	if len(params) == 0 || !strings.Contains(message, "{") {
+		return message
+	}
+	replacements := make([]string, 0, len(params)*2)
+	for name, value := range params {
+		replacements = append(replacements, "{"+name+"}", fmt.Sprint(value))
+	}
+	return strings.NewReplacer(replacements...).Replace(message)
+}
+
+// Dereference pointers and interfaces. Returns false if value is nil or invalid
+func indirect(value reflect.Value) (reflect.Value, bool) {
+	for value.IsValid() && (value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface) {
+		if value.IsNil() {
+			return value, false
+		}
+		value = value.Elem()
+	}
+	return value, value.IsValid()
+}
+
+// Value is nil, zero or empty slice, map or string
+func isEmpty(value reflect.Value) bool {
+	value, ok := indirect(value)
+	if !ok {
+		return true
+	}
+	switch value.Kind() {
+	case reflect.Slice, reflect.Map, reflect.Array, reflect.String, reflect.Chan:
+		return value.Len() == 0
+	}
+	return value.IsZero()
+}
+
+// Rule validates field and returns its violations
+type Rule interface {
+	Validate(field Field) []tiny_errors.FieldError
+}
+
+// RuleFunc is an adapter to use function as Rule
+type RuleFunc func(field Field) []tiny_errors.FieldError
+
+func (f RuleFunc) Validate(field Field) []tiny_errors.FieldError {
+	return f(field)
+}
+
+type checkRule struct {
+	name    string
+	message string
+	params  map[string]any
+	check   func(value reflect.Value) bool
+}
+
+// Create rule which reports violation with name, message and params if check returns false.
+// Check receives dereferenced value, nil values are not checked.
+//
+// Example:
+//
+//	even := validators.Check("even", "must be even", nil, func(value reflect.Value) bool {
+//		return value.CanInt() && value.Int()%2 == 0
+//	})
+func Check(name, message string, params map[string]any, check func(value reflect.Value) bool) Rule {
+	return &checkRule{name: name, message: message, params: params, check: check}
+}
+
+func (r *checkRule) Validate(field Field) []tiny_errors.FieldError {
+	value, ok := indirect(field.Value)
+	if !ok || r.check(value) {
+		return nil
+	}
+	return []tiny_errors.FieldError{field.Violation(r.name, r.message, r.params)}
+}
+
+// Create rule which checks format of string. Empty strings are not checked, values of other types are not valid.
+//
+// Example:
+//
+//	slug := validators.Format("slug", "must contain only lowercase letters, digits and dashes", func(value string) bool {
+//		return slugPattern.MatchString(value)
+//	})
+func Format(name, message string, valid func(value string) bool) Rule {
+	return Check(name, message, nil, func(value reflect.Value) bool {
+		if value.Kind() != reflect.String {
+			return false
+		}
+		return value.Len() == 0 || valid(value.String())
+	})
+}
+
+type andRule []Rule
+
+// All rules should pass. Rules are checked in order, violations of the first failed rule are returned
+func And(rules ...Rule) Rule {
+	if len(rules) == 1 {
+		return rules[0]
+	}
+	return andRule(rules)
+}
+
+func (r andRule) Validate(field Field) []tiny_errors.FieldError {
+	for _, rule := range r {
+		if _, ok := rule.(omitEmptyRule); ok {
+			if isEmpty(field.Value) {
+				return nil
+			}
+			continue
+		}
+		if violations := rule.Validate(field); len(violations) > 0 {
+			return violations
+		}
+	}
+	return nil
+}
+
+type omitEmptyRule struct{}
+
+// Skip the following rules of And if value is nil, zero or empty
+func OmitEmpty() Rule {
+	return omitEmptyRule{}
+}
+
+func (omitEmptyRule) Validate(field Field) []tiny_errors.FieldError {
+	return nil
+}
+
+type orRule []Rule
+
+// At least one of rules should pass. Otherwise one violation is returned with names of rules
+// separated by "|" and their messages separated by " or "
+func Or(rules ...Rule) Rule {
+	if len(rules) == 1 {
+		return rules[0]
+	}
+	return orRule(rules)
+}
+
+func (r orRule) Validate(field Field) []tiny_errors.FieldError {
+	var (
+		names    []string
+		messages []string
+	)
+	for _, rule := range r {
+		violations := rule.Validate(field)
+		if len(violations) == 0 {
+			return nil
+		}
+		for _, violation := range violations {
+			names = append(names, violation.Rule)
+			messages = append(messages, violation.Message)
+		}
+	}
+	return []tiny_errors.FieldError{field.Violation(strings.Join(names, "|"), strings.Join(messages, " or "), nil)}
+}
+
+type notRule struct {
+	rule Rule
+}
+
+// Rule should not pass. Violation has rule "not" and name of negated rule in params if it is known
+func Not(rule Rule) Rule {
+	return notRule{rule: rule}
+}
+
+func (r notRule) Validate(field Field) []tiny_errors.FieldError {
+	if len(r.rule.Validate(field)) > 0 {
+		return nil
+	}
+	if check, ok := r.rule.(*checkRule); ok {
+		return []tiny_errors.FieldError{field.Violation("not", "must not match rule {rule}", map[string]any{"rule": check.name})}
+	}
+	return []tiny_errors.FieldError{field.Violation("not", "is not allowed", nil)}
+}
+
+// Rule which validates elements of value by elements rule
+type elementsRule struct {
+	Rule
+	elements Rule
+
+	// Type of elements of value type. Returns false if value type has no elements
+	elementType func(t reflect.Type) (reflect.Type, bool)
+}
+
+// Every element of slice or array should pass rules. Paths of elements have index, e.g. "tags[0]"
+func Each(rules ...Rule) Rule {
+	rule := And(rules...)
+	return elementsRule{elements: rule, elementType: func(t reflect.Type) (reflect.Type, bool) {
+		if t == nil || (t.Kind() != reflect.Slice && t.Kind() != reflect.Array) {
+			return nil, false
+		}
+		return t.Elem(), true
+	}, Rule: RuleFunc(func(field Field) []tiny_errors.FieldError {
+		value, ok := indirect(field.Value)
+		if !ok || (value.Kind() != reflect.Slice && value.Kind() != reflect.Array) {
+			return nil
+		}
+		var violations []tiny_errors.FieldError
+		for i := 0; i < value.Len(); i++ {
+			path := fmt.Sprintf("%s[%d]", field.Path, i)
+			violations = append(violations, rule.Validate(field.child(path, value.Index(i)))...)
+		}
+		return violations
+	})}
+}
+
+// Every key of map should pass rules. Paths of keys are paths of map values, e.g. "labels.name"
+func Keys(rules ...Rule) Rule {
+	return mapRule(func(key, _ reflect.Value) reflect.Value { return key }, reflect.Type.Key, rules)
+}
+
+// Every value of map should pass rules. Paths of values have key, e.g. "labels.name"
+func Values(rules ...Rule) Rule {
+	return mapRule(func(_, value reflect.Value) reflect.Value { return value }, reflect.Type.Elem, rules)
+}
+
+func mapRule(target func(key, value reflect.Value) reflect.Value, targetType func(t reflect.Type) reflect.Type, rules []Rule) Rule {
+	rule := And(rules...)
+	return elementsRule{elements: rule, elementType: func(t reflect.Type) (reflect.Type, bool) {
+		if t == nil || t.Kind() != reflect.Map {
+			return nil, false
+		}
+		return targetType(t), true
+	}, Rule: RuleFunc(func(field Field) []tiny_errors.FieldError {
+		value, ok := indirect(field.Value)
+		if !ok || value.Kind() != reflect.Map {
+			return nil
+		}
+		var violations []tiny_errors.FieldError
+		for _, key := range sortedKeys(value) {
+			path := joinPath(field.Path, fmt.Sprint(key.Interface()))
+			violations = append(violations, rule.Validate(field.child(path, target(key, value.MapIndex(key))))...)
+		}
+		return violations
+	})}
+}
+
+// Keys of map sorted by their string representation, so violations have stable order
+func sortedKeys(value reflect.Value) []reflect.Value {
+	type namedKey struct {
+		name string
+		key  reflect.Value
+	}
+	named := make([]namedKey, 0, value.Len())
+	for _, key := range value.MapKeys() {
+		named = append(named, namedKey{name: fmt.Sprint(key.Interface()), key: key})
+	}
+	slices.SortFunc(named, func(a, b namedKey) int {
+		return strings.Compare(a.name, b.name)
+	})
+	keys := make([]reflect.Value, len(named))
+	for i, item := range named {
+		keys[i] = item.key
+	}
+	return keys
+}
+
+func joinPath(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+// Validate value by rules. Returns nil if value is valid, otherwise *tiny_errors.FieldErrors.
+//
+// Panics if rules use fields which value does not have, e.g. OnField with unknown name.
+//
+// Example:
+//
+//	errs := tiny_errors.NewFieldErrors()
+//	errs.MergePrefixed("name", validators.Var(req.Name, validators.Required(), validators.MaxLen(64)))
+func Var(value any, rules ...Rule) tiny_errors.ErrorHandler {
+	rule := And(rules...)
+	if err := checkFields(rule, nil, reflect.TypeOf(value)); err != nil {
+		panic("validators: " + err.Error())
+	}
+	violations := rule.Validate(Field{Value: reflect.ValueOf(value)})
+	return tiny_errors.NewFieldErrors(violations...).Err()
+}
@@ -0,0 +1,132 @@
+package validators
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/Moranilt/http-utils/tiny_errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func violations(value any, rules ...Rule) []tiny_errors.FieldError {
+	return And(rules...).Validate(Field{Path: "field", Value: reflect.ValueOf(value)})
+}
+
+func rules(violations []tiny_errors.FieldError) []string {
+	var result []string
+	for _, violation := range violations {
+		result = append(result, violation.Field+":"+violation.Rule)
+	}
+	return result
+}
+
+func TestBuiltinRules(t *testing.T) {
+	empty := ""
+	name := "John"
+	tests := []struct {
+		name  string
+		value any
+		rule  Rule
+		valid bool
+	}{
+		{"required string", "John", Required(), true},
+		{"required empty string", "", Required(), false},
+		{"required nil pointer", (*string)(nil), Required(), false},
+		{"required pointer to empty string", &empty, Required(), false},
+		{"required empty slice", []string{}, Required(), false},
+		{"min number", 18, Min(18), true},
+		{"min number too small", 17.5, Min(18), false},
+		{"min string", "Jo", Min(3), false},
+		{"min string of runes", "Жан", Min(3), true},
+		{"max slice", []int{1, 2, 3}, Max(2), false},
+		{"max map", map[string]int{"a": 1}, Max(2), true},
+		{"max of not sized value", true, Max(2), false},
+		{"len", "abc", Len(3), true},
+		{"min of nil pointer", (*int)(nil), Min(1), true},
+		{"eq", 5, Eq("5"), true},
+		{"ne", "admin", Ne("admin"), false},
+		{"oneof", "bank", OneOf("bank", "card"), true},
+		{"oneof pointer", &name, OneOf("bank", "card"), false},
+		{"regexp", "abc-1", Regexp(regexp.MustCompile(`^[a-z0-9-]+$`)), true},
+		{"uuid", "9aef6831-8538-4b03-8f61-7a687861584a", UUID(), true},
+		{"uuid empty", "", UUID(), true},
+		{"uuid not valid", "9aef6831", UUID(), false},
+		{"uuid of number", 5, UUID(), false},
+		{"date", "2023-08-15", Date(), true},
+		{"datetime", "2023-08-15", DateTime(), false},
+		{"int", "10", Int(), true},
+		{"url", "?test=name", URL(), false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.valid, len(violations(test.value, test.rule)) == 0)
+		})
+	}
+}
+
+func TestViolationMessage(t *testing.T) {
+	assert.Equal(t, []tiny_errors.FieldError{{
+		Field:   "field",
+		Rule:    "min",
+		Message: "must be at least 3 characters",
+		Params:  map[string]any{"min": float64(3)},
+	}}, violations("Jo", Min(3)))
+
+	assert.Equal(t, "must be at least 18", violations(17, Min(18))[0].Message)
+}
+
+func TestComposition(t *testing.T) {
+	t.Run("and stops on first failed rule", func(t *testing.T) {
+		assert.Equal(t, []string{"field:required"}, rules(violations("", Required(), Min(3))))
+	})
+
+	t.Run("omit empty", func(t *testing.T) {
+		assert.Empty(t, violations(0, OmitEmpty(), Min(18)))
+		assert.Equal(t, []string{"field:min"}, rules(violations(10, OmitEmpty(), Min(18))))
+	})
+
+	t.Run("or", func(t *testing.T) {
+		assert.Empty(t, violations("10", Or(UUID(), Int())))
+		result := violations("abc", Or(UUID(), Int()))
+		assert.Equal(t, []string{"field:uuid|int"}, rules(result))
+		assert.Equal(t, "must be a valid UUID or must be an integer", result[0].Message)
+	})
+
+	t.Run("not", func(t *testing.T) {
+		assert.Empty(t, violations("user", Not(OneOf("admin", "root"))))
+		result := violations("admin", Not(OneOf("admin", "root")))
+		assert.Equal(t, "must not match rule oneof", result[0].Message)
+	})
+
+	t.Run("each", func(t *testing.T) {
+		result := violations([]string{"a", "", "abcdef"}, Each(Required(), Max(3)))
+		assert.Equal(t, []string{"field[1]:required", "field[2]:max"}, rules(result))
+	})
+
+	t.Run("keys and values", func(t *testing.T) {
+		labels := map[string]string{"env": "", "x": "1", "owner": "team"}
+		assert.Equal(t, []string{"field.x:min"}, rules(violations(labels, Keys(Min(2)))))
+		assert.Equal(t, []string{"field.env:required"}, rules(violations(labels, Values(Required()))))
+	})
+}
+
+func TestCheck(t *testing.T) {
+	even := Check("even", "must be even", nil, func(value reflect.Value) bool {
+		return value.CanInt() && value.Int()%2 == 0
+	})
+
+	assert.Empty(t, violations(2, even))
+	assert.Equal(t, []string{"field:even"}, rules(violations(3, even)))
+}
+
+func TestVar(t *testing.T) {
+	assert.Nil(t, Var("John", Required(), Max(64)))
+
+	err := Var("", Required())
+	assert.Equal(t, tiny_errors.ERR_CODE_Validation, err.GetCode())
+
+	errs := tiny_errors.NewFieldErrors().MergePrefixed("name", err)
+	assert.Equal(t, []string{"name:required"}, rules(errs.Fields))
+}
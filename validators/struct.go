@@ -0,0 +1,247 @@
+package validators
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/Moranilt/http-utils/tiny_errors"
+)
+
+const TagValidate = "validate"
+
+// Rules of struct field parsed from tag. Rules of blank field "_" validate struct itself
+type fieldRules struct {
+	index []int
+	name  string
+	rule  Rule
+	self  bool
+}
+
+// Rules of struct type or error of its tags
+type structRulesResult struct {
+	rules []fieldRules
+	err   error
+}
+
+var tagCache sync.Map
+
+func clearTagCache() {
+	tagCache.Range(func(key, _ any) bool {
+		tagCache.Delete(key)
+		return true
+	})
+}
+
+// Validate struct by rules of "validate" tags. Nested structs, pointers to structs and structs in
+// slices and maps are validated too. Paths of fields are built from json tags, e.g. "items[0].name".
+// Rules of blank field "_" are applied to struct itself, e.g. `validate:"exclusive=email phone"`.
+// Returns nil if struct is valid, otherwise *tiny_errors.FieldErrors.
+//
+// Panics if tag is not valid or uses unknown fields, see Compile.
+//
+// Example:
+//
+//	type CreateUserRequest struct {
+//		Name  string   `json:"name" validate:"required,max=64"`
+//		Age   *int     `json:"age" validate:"omitempty,min=18"`
+//		Tags  []string `json:"tags" validate:"max=10,each(required,max=32)"`
+//		Owner *Owner   `json:"owner"`
+//	}
+//
+//	if err := validators.Struct(req); err != nil {
+//		return nil, err
+//	}
+func Struct(value any) tiny_errors.ErrorHandler {
+	return tiny_errors.NewFieldErrors(Violations(value)...).Err()
+}
+
+// Parse and check tags of struct type of value and types of its nested structs. Struct panics
+// with the same error, so services can call Compile at startup to find errors of tags before
+// the first request.
+//
+// Example:
+//
+//	if err := validators.Compile(CreateUserRequest{}); err != nil {
+//		log.Fatal(err)
+//	}
+func Compile(value any) error {
+	return compileType(reflect.TypeOf(value), make(map[reflect.Type]bool))
+}
+
+func compileType(t reflect.Type, seen map[reflect.Type]bool) error {
+	for t != nil && (t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map) {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct || seen[t] {
+		return nil
+	}
+	seen[t] = true
+
+	rules, err := structRules(t)
+	if err != nil {
+		return err
+	}
+	for _, field := range rules {
+		if field.self {
+			continue
+		}
+		if err := compileType(t.FieldByIndex(field.index).Type, seen); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Violations of rules of "validate" tags, see Struct
+func Violations(value any) []tiny_errors.FieldError {
+	var violations []tiny_errors.FieldError
+	walk(reflect.ValueOf(value), "", &violations)
+	return violations
+}
+
+// Validate fields of structs in value and its nested values
+func walk(value reflect.Value, path string, violations *[]tiny_errors.FieldError) {
+	value, ok := indirect(value)
+	if !ok {
+		return
+	}
+
+	switch value.Kind() {
+	case reflect.Struct:
+		rules, err := structRules(value.Type())
+		if err != nil {
+			panic("validators: " + err.Error())
+		}
+		for _, field := range rules {
+			if field.self {
+				*violations = append(*violations, field.rule.Validate(Field{Path: path, Value: value})...)
+				continue
+			}
+			fieldValue, ok := fieldByIndex(value, field.index)
+			if !ok {
+				continue
+			}
+			fieldPath := joinPath(path, field.name)
+			if field.rule != nil {
+				*violations = append(*violations, field.rule.Validate(Field{Path: fieldPath, Value: fieldValue, Parent: value})...)
+			}
+			walk(fieldValue, fieldPath, violations)
+		}
+	case reflect.Slice, reflect.Array:
+		if !containsStructs(value.Type().Elem()) {
+			return
+		}
+		for i := 0; i < value.Len(); i++ {
+			walk(value.Index(i), fmt.Sprintf("%s[%d]", path, i), violations)
+		}
+	case reflect.Map:
+		if !containsStructs(value.Type().Elem()) {
+			return
+		}
+		for _, key := range sortedKeys(value) {
+			walk(value.MapIndex(key), joinPath(path, fmt.Sprint(key.Interface())), violations)
+		}
+	}
+}
+
+// Type may contain structs with fields to validate
+func containsStructs(t reflect.Type) bool {
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct || t.Kind() == reflect.Interface
+}
+
+// Field of struct by index. Returns false if field is in nil embedded pointer
+func fieldByIndex(value reflect.Value, index []int) (reflect.Value, bool) {
+	for i, fieldIndex := range index {
+		if i > 0 {
+			var ok bool
+			if value, ok = indirect(value); !ok {
+				return value, false
+			}
+		}
+		value = value.Field(fieldIndex)
+	}
+	return value, true
+}
+
+// Rules of fields of struct type including fields of embedded structs
+func structRules(t reflect.Type) ([]fieldRules, error) {
+	if cached, ok := tagCache.Load(t); ok {
+		result := cached.(structRulesResult)
+		return result.rules, result.err
+	}
+	rules, err := collectRules(t, t, nil)
+	tagCache.Store(t, structRulesResult{rules: rules, err: err})
+	return rules, err
+}
+
+// Rules of fields of t. Root is the validated struct which contains t as embedded struct
+func collectRules(root, t reflect.Type, parent []int) ([]fieldRules, error) {
+	var rules []fieldRules
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		index := append(append([]int{}, parent...), i)
+		tag := field.Tag.Get(TagValidate)
+		if tag == "-" {
+			continue
+		}
+
+		if field.Name == "_" {
+			if tag == "" {
+				continue
+			}
+			rule, err := ParseTag(tag)
+			if err == nil {
+				err = checkFields(rule, nil, root)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("field _ of %s: %w", t, err)
+			}
+			rules = append(rules, fieldRules{rule: rule, self: true})
+			continue
+		}
+
+		jsonTag, hasJSON := field.Tag.Lookup("json")
+		name, _, _ := strings.Cut(jsonTag, ",")
+		if jsonTag == "-" {
+			name = field.Name
+		}
+
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+		if field.Anonymous && (!hasJSON || name == "") && fieldType.Kind() == reflect.Struct && tag == "" {
+			embedded, err := collectRules(root, fieldType, index)
+			if err != nil {
+				return nil, err
+			}
+			rules = append(rules, embedded...)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		var rule Rule
+		if tag != "" {
+			parsed, err := ParseTag(tag)
+			if err == nil {
+				err = checkFields(parsed, root, field.Type)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("field %s of %s: %w", field.Name, t, err)
+			}
+			rule = parsed
+		}
+		rules = append(rules, fieldRules{index: index, name: name, rule: rule})
+	}
+	return rules, nil
+}
@@ -0,0 +1,87 @@
+package validators
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Moranilt/http-utils/tiny_errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type testAddress struct {
+	City string `json:"city" validate:"required"`
+}
+
+type testOwner struct {
+	Email   string       `json:"email" validate:"required"`
+	Address *testAddress `json:"address"`
+}
+
+type testBase struct {
+	ID string `json:"id" validate:"omitempty,uuid"`
+}
+
+type testRequest struct {
+	testBase
+	Name      string            `json:"name" validate:"required,max=8"`
+	Age       *int              `json:"age,omitempty" validate:"omitempty,min=18"`
+	Tags      []string          `json:"tags" validate:"max=3,each(required)"`
+	Owner     *testOwner        `json:"owner" validate:"required"`
+	Items     []testAddress     `json:"items"`
+	Labels    map[string]string `json:"labels" validate:"values(max=4)"`
+	Internal  string            `json:"-" validate:"required"`
+	Ignored   string            `json:"ignored" validate:"-"`
+	CreatedAt time.Time         `json:"created_at"`
+	secret    string
+}
+
+func TestStruct(t *testing.T) {
+	age := 17
+	req := testRequest{
+		testBase: testBase{ID: "not uuid"},
+		Name:     "Johnathan Doe",
+		Age:      &age,
+		Tags:     []string{"a", ""},
+		Owner:    &testOwner{Address: &testAddress{}},
+		Items:    []testAddress{{City: "Berlin"}, {}},
+		Labels:   map[string]string{"env": "production", "team": "core"},
+	}
+
+	err := Struct(&req)
+	if !assert.NotNil(t, err) {
+		return
+	}
+	assert.Equal(t, tiny_errors.ERR_CODE_Validation, err.GetCode())
+	assert.Equal(t, []string{
+		"id:uuid",
+		"name:max",
+		"age:min",
+		"tags[1]:required",
+		"owner.email:required",
+		"owner.address.city:required",
+		"items[1].city:required",
+		"labels.env:max",
+		"Internal:required",
+	}, rules(err.(*tiny_errors.FieldErrors).Fields))
+}
+
+func TestStructValid(t *testing.T) {
+	req := testRequest{
+		Name:     "John",
+		Owner:    &testOwner{Email: "john@example.com"},
+		Internal: "value",
+	}
+
+	assert.Nil(t, Struct(req))
+	assert.Nil(t, Struct(nil))
+	assert.Equal(t, []string{"owner:required", "Internal:required"}, rules(Violations(testRequest{Name: "John"})))
+}
+
+func TestStructNotValidTag(t *testing.T) {
+	type request struct {
+		Name string `validate:"required,unknown"`
+	}
+
+	assert.ErrorIs(t, Compile(request{}), ErrUnknownRule)
+	assert.Panics(t, func() { Struct(request{}) })
+}